@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"bingxGo/config"
 	"bingxGo/internal/binance"
@@ -11,8 +13,17 @@ import (
 	"bingxGo/internal/telegram"
 )
 
+const rulesPath = "config/parser_rules.json"
+
 func main() {
-	client := binance.NewClient()
+	if err := parser.WatchRules(context.Background(), rulesPath, 5*time.Second); err != nil {
+		log.Printf("Using built-in parser rules: %v", err)
+	}
+
+	client, err := binance.NewClient()
+	if err != nil {
+		log.Fatalf("Error creating Binance client: %v", err)
+	}
 
 	pairs, err := client.FetchPairs()
 	if err != nil {
@@ -60,4 +71,8 @@ func main() {
 		pairs := parser.ExtractPairs(t)
 		fmt.Printf("Title: %q → Pairs: %v\n", t, pairs)
 	}
+
+	for _, u := range parser.UnmatchedTitles() {
+		log.Printf("No parser rule matched %q (seen %d times); add a rule to %s", u.Title, u.Count, rulesPath)
+	}
 }
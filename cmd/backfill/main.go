@@ -0,0 +1,177 @@
+// Command backfill pages through Binance announcement history and stores
+// every article in a local corpus file for parser regression tests and
+// strategy backtests. Re-running it resumes: stored articles are skipped.
+//
+//	go run ./cmd/backfill -catalogs 161,48 -out ./data/corpus.jsonl -bodies
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"bingxGo/internal/binance"
+	"bingxGo/internal/corpus"
+)
+
+func main() {
+	catalogs := flag.String("catalogs", strconv.Itoa(binance.DelistingCatalogID), "comma-separated catalog IDs")
+	out := flag.String("out", "./data/corpus.jsonl", "corpus file")
+	pageSize := flag.Int("page-size", 50, "articles per page (max 50)")
+	maxPages := flag.Int("pages", 0, "pages per catalog, 0 for all")
+	bodies := flag.Bool("bodies", false, "fetch article bodies from the detail API")
+	delay := flag.Duration("delay", 500*time.Millisecond, "pause between requests")
+	proxy := flag.String("proxy", "", "HTTP or SOCKS5 proxy URL")
+	flag.Parse()
+
+	ids, err := parseCatalogs(*catalogs)
+	if err != nil {
+		log.Fatalf("Invalid -catalogs: %v", err)
+	}
+
+	client, err := binance.NewClientWithConfig(binance.ClientConfig{Proxy: *proxy})
+	if err != nil {
+		log.Fatalf("Error creating Binance client: %v", err)
+	}
+
+	store, err := corpus.Open(*out)
+	if err != nil {
+		log.Fatalf("Error opening corpus: %v", err)
+	}
+	defer store.Close()
+
+	b := &backfill{
+		client: client,
+		store:  store,
+		bodies: *bodies,
+		delay:  *delay,
+	}
+	for _, id := range ids {
+		added, err := b.catalog(id, *pageSize, *maxPages)
+		if err != nil {
+			log.Printf("Catalog %d stopped: %v", id, err)
+		}
+		log.Printf("Catalog %d: %d new articles", id, added)
+	}
+	fmt.Printf("Corpus %s holds %d articles\n", *out, store.Len())
+}
+
+type backfill struct {
+	client *binance.BinanceClient
+	store  *corpus.Corpus
+	bodies bool
+	delay  time.Duration
+}
+
+// catalog walks one catalog page by page and stores unknown articles.
+func (b *backfill) catalog(catalogID, pageSize, maxPages int) (int, error) {
+	added := 0
+	seen := 0
+	for pageNo := 1; maxPages == 0 || pageNo <= maxPages; pageNo++ {
+		var page *binance.NewsPage
+		err := b.retry(func() (err error) {
+			page, err = b.client.GetNewsPage(catalogID, pageNo, pageSize)
+			return err
+		})
+		if err != nil {
+			return added, fmt.Errorf("page %d: %w", pageNo, err)
+		}
+		if len(page.Articles) == 0 {
+			break
+		}
+
+		for _, a := range page.Articles {
+			seen++
+			// Stored entries are kept unless they still lack a wanted body.
+			if old, ok := b.store.Get(a.ID); ok && (!b.bodies || old.Body != "") {
+				continue
+			}
+			e, ok := b.entry(a)
+			if !ok {
+				continue
+			}
+			if err := b.store.Add(e); err != nil {
+				return added, err
+			}
+			added++
+		}
+		log.Printf("Catalog %d page %d: %d/%d articles", catalogID, pageNo, seen, page.Total)
+
+		if page.Total > 0 && seen >= page.Total {
+			break
+		}
+		time.Sleep(b.delay)
+	}
+	return added, nil
+}
+
+// entry builds the corpus entry for a, fetching its body when wanted.
+// It reports false when a stored entry would only be appended again
+// because its body could still not be fetched.
+func (b *backfill) entry(a binance.NewsArticle) (corpus.Entry, bool) {
+	e := corpus.Entry{
+		ID:          a.ID,
+		Code:        a.Code,
+		CatalogID:   a.CatalogID,
+		Title:       a.Title,
+		ReleaseDate: a.ReleaseDate,
+		FetchedAt:   time.Now().UTC(),
+	}
+	stored := b.store.Has(a.ID)
+	if !b.bodies || a.Code == "" {
+		return e, !stored
+	}
+
+	time.Sleep(b.delay)
+	err := b.retry(func() error {
+		article, err := b.client.GetArticle(a.Code)
+		if err == nil {
+			e.Body = article.Body
+		}
+		return err
+	})
+	if err != nil {
+		log.Printf("Body of %d (%s) skipped: %v", a.ID, a.Code, err)
+	}
+	return e, !stored || e.Body != ""
+}
+
+// retry runs fn, waiting out rate limits with growing pauses.
+func (b *backfill) retry(fn func() error) error {
+	wait := 30 * time.Second
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		limited, retryAfter := binance.RateLimited(err)
+		if !limited || attempt == 5 {
+			return err
+		}
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		log.Printf("Rate limited, waiting %s: %v", wait, err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func parseCatalogs(s string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %q: %w", part, err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no catalogs given")
+	}
+	return ids, nil
+}
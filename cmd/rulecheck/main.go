@@ -0,0 +1,50 @@
+// Command rulecheck validates a parser rules file and lists the corpus
+// titles none of its rules catch, before the file goes live.
+//
+//	go run ./cmd/rulecheck -rules config/parser_rules.json -corpus ./data/corpus.jsonl
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"bingxGo/internal/binance"
+	"bingxGo/internal/corpus"
+	"bingxGo/internal/parser"
+)
+
+func main() {
+	rulesPath := flag.String("rules", "config/parser_rules.json", "rules file")
+	corpusPath := flag.String("corpus", "./data/corpus.jsonl", "corpus file from cmd/backfill")
+	catalog := flag.Int("catalog", binance.DelistingCatalogID, "only check this catalog, 0 for all")
+	flag.Parse()
+
+	rules, err := parser.LoadRules(*rulesPath)
+	if err != nil {
+		log.Fatalf("Invalid rules: %v", err)
+	}
+	fmt.Printf("%d rules OK: %v\n", len(rules.Names()), rules.Names())
+
+	entries, err := corpus.Load(*corpusPath)
+	if err != nil {
+		log.Fatalf("Error loading corpus: %v", err)
+	}
+
+	var titles []string
+	for _, e := range entries {
+		if *catalog == 0 || e.CatalogID == *catalog {
+			titles = append(titles, e.Title)
+		}
+	}
+
+	missed := rules.Unmatched(titles)
+	fmt.Printf("%d of %d titles unmatched\n", len(missed), len(titles))
+	for _, t := range missed {
+		fmt.Printf("  %s\n", t)
+	}
+	if len(missed) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,90 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ====== TYPES ======
+
+// Article is a full announcement from the CMS detail API. Body is the
+// raw CMS content: a JSON-encoded rich-text tree, or HTML for older posts.
+type Article struct {
+	ID          int64  `json:"id"`
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	URL         string `json:"-"`
+	ReleaseDate int64  `json:"publishDate"` // unix milliseconds
+}
+
+type articleResponse struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Data    *Article `json:"data"`
+}
+
+const articleDetailPath = "/bapi/apex/v1/public/apex/cms/article/detail/query"
+
+// ErrNoArticle is returned when the detail API has no article for a query.
+var ErrNoArticle = errors.New("no such Binance article")
+
+// Released returns the release date as time.
+func (a Article) Released() time.Time {
+	return time.UnixMilli(a.ReleaseDate)
+}
+
+// NewsArticle returns the list form of the article, without a catalog.
+func (a Article) NewsArticle() NewsArticle {
+	return NewsArticle{
+		ID:          a.ID,
+		Code:        a.Code,
+		Title:       a.Title,
+		URL:         a.URL,
+		ReleaseDate: a.ReleaseDate,
+	}
+}
+
+// ====== FETCH ARTICLE ======
+
+// GetArticle fetches one announcement by code (NewsArticle.Code).
+func (b *BinanceClient) GetArticle(code string) (*Article, error) {
+	article, err := b.getArticle("articleCode=" + url.QueryEscape(code))
+	if err != nil {
+		return nil, fmt.Errorf("fetching Binance article %s: %w", code, err)
+	}
+	return article, nil
+}
+
+// GetArticleByID fetches one announcement by numeric ID. IDs grow with
+// every article Binance publishes, so probing the next ones can reveal an
+// article before any list does. ErrNoArticle means the ID is not taken yet.
+func (b *BinanceClient) GetArticleByID(id int64) (*Article, error) {
+	article, err := b.getArticle("articleId=" + strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, fmt.Errorf("fetching Binance article %d: %w", id, err)
+	}
+	return article, nil
+}
+
+func (b *BinanceClient) getArticle(query string) (*Article, error) {
+	u := fmt.Sprintf("%s%s?%s", b.cfg.WebURL, articleDetailPath, query)
+
+	var result articleResponse
+	if err := b.getJSON(u, b.cfg.NewsTimeout, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != "000000" {
+		return nil, fmt.Errorf("Binance article API error %s: %s", result.Code, result.Message)
+	}
+	if result.Data == nil || (result.Data.ID == 0 && result.Data.Code == "") {
+		return nil, ErrNoArticle
+	}
+	if result.Data.Code != "" {
+		result.Data.URL = b.cfg.WebURL + announcementPath + result.Data.Code
+	}
+	return result.Data, nil
+}
@@ -1,22 +1,29 @@
 package binance
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"strings"
 	"time"
 )
 
 // ====== TYPES ======
 
 type BinanceClient struct {
+	cfg    ClientConfig
 	client *http.Client
 }
 
+// BinancePair is a USDⓈ-M futures symbol from exchangeInfo.
 type BinancePair struct {
-	Symbol string `json:"symbol"`
+	Symbol       string `json:"symbol"`
+	Pair         string `json:"pair"`
+	ContractType string `json:"contractType"` // PERPETUAL, CURRENT_QUARTER, NEXT_QUARTER, ...
+	Status       string `json:"status"`       // TRADING, SETTLING, PENDING_TRADING, CLOSE, ...
+	DeliveryDate int64  `json:"deliveryDate"` // unix milliseconds
+	OnboardDate  int64  `json:"onboardDate"`  // unix milliseconds
+	BaseAsset    string `json:"baseAsset"`
+	QuoteAsset   string `json:"quoteAsset"`
+	MarginAsset  string `json:"marginAsset"`
 }
 
 type exchangeInfoResponse struct {
@@ -24,77 +31,196 @@ type exchangeInfoResponse struct {
 }
 
 type NewsArticle struct {
-	ID          string `json:"id"`
+	ID          int64  `json:"id"`
+	Code        string `json:"code"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	URL         string `json:"code"`
-	ReleaseDate int64  `json:"releaseDate"`
+	URL         string `json:"-"`
+	ReleaseDate int64  `json:"releaseDate"` // unix milliseconds
+	CatalogID   int    `json:"-"`
+}
+
+// NewsPage is one page of a catalog listing.
+type NewsPage struct {
+	Articles []NewsArticle
+	Total    int
+}
+
+// newsCatalog mirrors data.catalogs[]; catalogs can nest sub-catalogs
+// with their own articles.
+type newsCatalog struct {
+	CatalogID   int           `json:"catalogId"`
+	CatalogName string        `json:"catalogName"`
+	Total       int           `json:"total"`
+	Articles    []NewsArticle `json:"articles"`
+	Catalogs    []newsCatalog `json:"catalogs"`
 }
 
 type newsResponse struct {
-	Data struct {
-		Articles []NewsArticle `json:"catalogs"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Data    struct {
+		Catalogs []newsCatalog `json:"catalogs"`
 	} `json:"data"`
 }
 
+// Futures contract types and symbol states.
+const (
+	ContractPerpetual = "PERPETUAL"
+	StatusTrading     = "TRADING"
+	StatusSettling    = "SETTLING"
+)
+
+// perpetualDeliveryDate is the far-future deliveryDate (2100-12-25) carried
+// by perpetuals that have no scheduled delisting.
+const perpetualDeliveryDate = 4133404800000
+
+// Delivery returns the delivery date as time.
+func (p BinancePair) Delivery() time.Time {
+	return time.UnixMilli(p.DeliveryDate)
+}
+
+// Onboard returns the listing date as time.
+func (p BinancePair) Onboard() time.Time {
+	return time.UnixMilli(p.OnboardDate)
+}
+
+// DeliveryScheduled reports whether a perpetual has a real delivery date,
+// which Binance sets once the contract is going to be delisted.
+func (p BinancePair) DeliveryScheduled() bool {
+	return p.ContractType == ContractPerpetual && p.DeliveryDate > 0 && p.DeliveryDate < perpetualDeliveryDate
+}
+
+// Released returns the release date as time.
+func (a NewsArticle) Released() time.Time {
+	return time.UnixMilli(a.ReleaseDate)
+}
+
+// Announcement catalogs.
+const (
+	NewListingCatalogID = 48  // New Cryptocurrency Listing (spot and futures)
+	LatestNewsCatalogID = 49  // Latest Binance News (monitoring tags, margin changes)
+	DelistingCatalogID  = 161 // Delisting (spot, margin and futures removals)
+)
+
+const (
+	newsListPath     = "/bapi/apex/v1/public/apex/cms/article/list/query"
+	announcementPath = "/en/support/announcement/"
+	maxNewsPageSize  = 50
+)
+
 // ====== CONSTRUCTOR ======
 
-func NewClient() *BinanceClient {
-	return &BinanceClient{
-		client: &http.Client{Timeout: 15 * time.Second},
-	}
+// NewClient creates a client with the default endpoints and headers.
+func NewClient() (*BinanceClient, error) {
+	return NewClientWithConfig(ClientConfig{})
 }
 
 // ====== FETCH NEWS ======
 
-func (b *BinanceClient) GetNews() ([]NewsArticle, error) {
-	const url = "https://www.binance.com/bapi/apex/v1/public/apex/cms/article/list/query?type=1&pageNo=1&pageSize=10&catalogId=161"
-
-	resp, err := b.client.Get(url)
+// GetNews returns one page of announcements from a catalog.
+//
+// Example: articles, _ := client.GetNews(binance.DelistingCatalogID, 1, 20)
+func (b *BinanceClient) GetNews(catalogID, pageNo, pageSize int) ([]NewsArticle, error) {
+	page, err := b.GetNewsPage(catalogID, pageNo, pageSize)
 	if err != nil {
-		return nil, fmt.Errorf("fetching Binance news: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return page.Articles, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("non-200 status %d: %s", resp.StatusCode, string(body))
+// GetNewsPage returns one page of announcements along with the catalog total.
+func (b *BinanceClient) GetNewsPage(catalogID, pageNo, pageSize int) (*NewsPage, error) {
+	if pageNo < 1 {
+		pageNo = 1
 	}
+	if pageSize < 1 || pageSize > maxNewsPageSize {
+		pageSize = maxNewsPageSize
+	}
+
+	url := fmt.Sprintf("%s%s?type=1&pageNo=%d&pageSize=%d&catalogId=%d",
+		b.cfg.WebURL, newsListPath, pageNo, pageSize, catalogID)
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding Binance news JSON: %w", err)
+	var result newsResponse
+	if err := b.getJSON(url, b.cfg.NewsTimeout, &result); err != nil {
+		return nil, fmt.Errorf("fetching Binance news: %w", err)
+	}
+	if result.Code != "000000" {
+		return nil, fmt.Errorf("Binance news API error %s: %s", result.Code, result.Message)
 	}
 
-	// For simplicity, we only log success here.
-	fmt.Println("✅ Binance news fetched successfully")
-	return nil, nil
+	page := &NewsPage{}
+	collectArticles(result.Data.Catalogs, b.cfg.WebURL+announcementPath, page)
+	return page, nil
 }
 
-// ====== FETCH PAIRS ======
+// GetAllNews pages through a catalog until every article is collected.
+// maxPages bounds the walk; 0 means no limit.
+func (b *BinanceClient) GetAllNews(catalogID, pageSize, maxPages int) ([]NewsArticle, error) {
+	var all []NewsArticle
+	seen := make(map[int64]bool)
 
-func (b *BinanceClient) FetchPairs() ([]BinancePair, error) {
-	const url = "https://fapi.binance.com/fapi/v1/exchangeInfo"
+	for pageNo := 1; maxPages == 0 || pageNo <= maxPages; pageNo++ {
+		page, err := b.GetNewsPage(catalogID, pageNo, pageSize)
+		if err != nil {
+			return all, fmt.Errorf("page %d: %w", pageNo, err)
+		}
 
-	resp, err := b.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("fetching pairs: %w", err)
+		added := 0
+		for _, a := range page.Articles {
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				all = append(all, a)
+				added++
+			}
+		}
+		if added == 0 || (page.Total > 0 && len(all) >= page.Total) {
+			break
+		}
 	}
-	defer resp.Body.Close()
+	return all, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("non-200 status %d: %s", resp.StatusCode, string(body))
+// collectArticles flattens catalogs[].articles[] including nested catalogs.
+func collectArticles(catalogs []newsCatalog, linkBase string, page *NewsPage) {
+	for _, c := range catalogs {
+		// A parent's total already covers its sub-catalogs.
+		if len(c.Articles) > 0 || len(c.Catalogs) == 0 {
+			page.Total += c.Total
+		}
+		for _, a := range c.Articles {
+			a.CatalogID = c.CatalogID
+			if a.Code != "" {
+				a.URL = linkBase + a.Code
+			}
+			page.Articles = append(page.Articles, a)
+		}
+		collectArticles(c.Catalogs, linkBase, page)
 	}
+}
 
+// ====== FETCH PAIRS ======
+
+// FetchExchangeInfo returns every USDⓈ-M futures symbol in any state.
+func (b *BinanceClient) FetchExchangeInfo() ([]BinancePair, error) {
 	var data exchangeInfoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("decoding pairs JSON: %w", err)
+	if err := b.getJSON(b.cfg.FuturesURL+"/fapi/v1/exchangeInfo", b.cfg.ExchangeInfoTimeout, &data); err != nil {
+		return nil, fmt.Errorf("fetching exchange info: %w", err)
+	}
+	return data.Symbols, nil
+}
+
+// FetchPairs returns the USDT-quoted perpetuals that are currently trading.
+func (b *BinanceClient) FetchPairs() ([]BinancePair, error) {
+	symbols, err := b.FetchExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("fetching pairs: %w", err)
 	}
 
-	usdtPairs := make([]BinancePair, 0, len(data.Symbols))
-	for _, s := range data.Symbols {
-		if strings.HasSuffix(s.Symbol, "USDT") {
+	usdtPairs := make([]BinancePair, 0, len(symbols))
+	for _, s := range symbols {
+		if s.QuoteAsset == "USDT" && s.ContractType == ContractPerpetual && s.Status == StatusTrading {
 			usdtPairs = append(usdtPairs, s)
 		}
 	}
@@ -0,0 +1,207 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingServer serves handle and keeps the requests it saw.
+type recordingServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	reqs []*http.Request
+}
+
+func newRecordingServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request)) *recordingServer {
+	t.Helper()
+	s := &recordingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.reqs = append(s.reqs, r)
+		s.mu.Unlock()
+		handle(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *recordingServer) last(t *testing.T) *http.Request {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.reqs) == 0 {
+		t.Fatal("server saw no request")
+	}
+	return s.reqs[len(s.reqs)-1]
+}
+
+func newTestClient(t *testing.T, cfg ClientConfig) *BinanceClient {
+	t.Helper()
+	c, err := NewClientWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+const exchangeInfoBody = `{"symbols":[
+ {"symbol":"ABCUSDT","contractType":"PERPETUAL","status":"TRADING","baseAsset":"ABC","quoteAsset":"USDT"},
+ {"symbol":"XYZUSDT","contractType":"PERPETUAL","status":"SETTLING","baseAsset":"XYZ","quoteAsset":"USDT"}]}`
+
+func TestClientBaseURLsAndHeaders(t *testing.T) {
+	s := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v1/exchangeInfo", "/api/v3/exchangeInfo":
+			fmt.Fprint(w, exchangeInfoBody)
+		case newsListPath:
+			fmt.Fprint(w, `{"code":"000000","data":{"catalogs":[{"catalogId":161,"total":1,
+			 "articles":[{"id":7,"code":"abc","title":"Binance Will Delist ABC"}]}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	c := newTestClient(t, ClientConfig{
+		FuturesURL:     s.URL + "/",
+		SpotURL:        s.URL,
+		WebURL:         s.URL,
+		UserAgent:      "test-agent",
+		AcceptLanguage: "de-DE",
+		Lang:           "de",
+		Headers:        map[string]string{"X-Test": "1"},
+	})
+
+	pairs, err := c.FetchPairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 || pairs[0].Symbol != "ABCUSDT" {
+		t.Errorf("FetchPairs = %+v", pairs)
+	}
+	r := s.last(t)
+	if r.URL.Path != "/fapi/v1/exchangeInfo" {
+		t.Errorf("futures request went to %s", r.URL.Path)
+	}
+	for k, want := range map[string]string{
+		"User-Agent":      "test-agent",
+		"Accept":          "application/json",
+		"Accept-Language": "de-DE",
+		"Lang":            "de",
+		"X-Test":          "1",
+	} {
+		if got := r.Header.Get(k); got != want {
+			t.Errorf("header %s = %q, want %q", k, got, want)
+		}
+	}
+
+	if _, err := c.FetchSpotExchangeInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if p := s.last(t).URL.Path; p != "/api/v3/exchangeInfo" {
+		t.Errorf("spot request went to %s", p)
+	}
+
+	articles, err := c.GetNews(DelistingCatalogID, 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].URL != s.URL+announcementPath+"abc" {
+		t.Errorf("GetNews = %+v", articles)
+	}
+}
+
+func TestClientProxy(t *testing.T) {
+	proxy := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, exchangeInfoBody)
+	})
+	c := newTestClient(t, ClientConfig{FuturesURL: "http://fapi.binance.invalid", Proxy: proxy.URL})
+
+	if _, err := c.FetchExchangeInfo(); err != nil {
+		t.Fatal(err)
+	}
+	// A forward proxy gets the absolute target URL.
+	if r := proxy.last(t); r.URL.Host != "fapi.binance.invalid" || r.URL.Path != "/fapi/v1/exchangeInfo" {
+		t.Errorf("proxy saw %s", r.URL)
+	}
+}
+
+func TestClientInvalidProxy(t *testing.T) {
+	for _, proxy := range []string{"ftp://host:21", "http://[::1"} {
+		if _, err := NewClientWithConfig(ClientConfig{Proxy: proxy}); err == nil {
+			t.Errorf("proxy %q accepted", proxy)
+		}
+	}
+}
+
+func TestClientStatusErrors(t *testing.T) {
+	code := http.StatusTooManyRequests
+	s := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "slow down", code)
+	})
+	c := newTestClient(t, ClientConfig{FuturesURL: s.URL})
+
+	_, err := c.FetchExchangeInfo()
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != code {
+		t.Fatalf("err = %v, want a %d StatusError", err, code)
+	}
+	if limited, wait := RateLimited(err); !limited || wait != 30*time.Second {
+		t.Errorf("RateLimited = %v, %v", limited, wait)
+	}
+
+	code = http.StatusInternalServerError
+	_, err = c.FetchExchangeInfo()
+	if !errors.As(err, &se) || se.Code != code {
+		t.Fatalf("err = %v, want a %d StatusError", err, code)
+	}
+	if limited, _ := RateLimited(err); limited {
+		t.Error("500 reported as rate limited")
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	s := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) { <-release })
+	defer close(release)
+	c := newTestClient(t, ClientConfig{FuturesURL: s.URL, ExchangeInfoTimeout: 20 * time.Millisecond})
+
+	if _, err := c.FetchExchangeInfo(); err == nil {
+		t.Fatal("request outlived ExchangeInfoTimeout")
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	s := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss><channel><item>
+		 <title>Binance Will Delist ABC</title>
+		 <link>https://www.binance.com/en/support/announcement/abc123/</link>
+		 <pubDate>Mon, 30 Sep 2024 03:00:00 +0000</pubDate>
+		</item></channel></rss>`)
+	})
+	c := newTestClient(t, ClientConfig{FeedURL: s.URL, Headers: map[string]string{"X-Test": "1"}})
+
+	articles, err := c.GetFeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].Code != "abc123" ||
+		articles[0].ReleaseDate != time.Date(2024, 9, 30, 3, 0, 0, 0, time.UTC).UnixMilli() {
+		t.Errorf("GetFeed = %+v", articles)
+	}
+	// The feed is not Binance: none of the Binance headers go to it.
+	r := s.last(t)
+	for _, k := range []string{"Lang", "Accept-Language", "X-Test"} {
+		if v := r.Header.Get(k); v != "" {
+			t.Errorf("feed request carried %s: %q", k, v)
+		}
+	}
+
+	if _, err := newTestClient(t, ClientConfig{}).GetFeed(); !errors.Is(err, ErrNoFeed) {
+		t.Errorf("GetFeed without FeedURL = %v", err)
+	}
+}
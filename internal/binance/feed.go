@@ -0,0 +1,138 @@
+package binance
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// ====== TYPES ======
+
+// catalogListResponse is the composite CMS endpoint behind the
+// announcement pages. It is served from a different cache than the apex
+// list, so either may reveal a new article first.
+type catalogListResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		CatalogID int           `json:"catalogId"`
+		Total     int           `json:"total"`
+		Articles  []NewsArticle `json:"articles"`
+		Catalogs  []newsCatalog `json:"catalogs"`
+	} `json:"data"`
+}
+
+type rssFeed struct {
+	Items []rssItem `xml:"channel>item"`
+}
+
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+}
+
+const catalogListPath = "/bapi/composite/v1/public/cms/article/catalog/list/query"
+
+// ErrNoFeed is returned by GetFeed when no feed URL is configured.
+var ErrNoFeed = errors.New("no announcement feed configured")
+
+// ====== CATALOG LIST ======
+
+// GetCatalogNews returns one page of a catalog from the composite CMS
+// endpoint. Articles match GetNews, so both can be raced and deduplicated
+// by ID.
+func (b *BinanceClient) GetCatalogNews(catalogID, pageNo, pageSize int) ([]NewsArticle, error) {
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	if pageSize < 1 || pageSize > maxNewsPageSize {
+		pageSize = maxNewsPageSize
+	}
+
+	url := fmt.Sprintf("%s%s?catalogId=%d&pageNo=%d&pageSize=%d",
+		b.cfg.WebURL, catalogListPath, catalogID, pageNo, pageSize)
+
+	var result catalogListResponse
+	if err := b.getJSON(url, b.cfg.NewsTimeout, &result); err != nil {
+		return nil, fmt.Errorf("fetching Binance catalog news: %w", err)
+	}
+	if result.Code != "000000" {
+		return nil, fmt.Errorf("Binance catalog API error %s: %s", result.Code, result.Message)
+	}
+
+	page := &NewsPage{}
+	linkBase := b.cfg.WebURL + announcementPath
+	for _, a := range result.Data.Articles {
+		a.CatalogID = catalogID
+		if a.Code != "" {
+			a.URL = linkBase + a.Code
+		}
+		page.Articles = append(page.Articles, a)
+	}
+	collectArticles(result.Data.Catalogs, linkBase, page)
+	return page.Articles, nil
+}
+
+// ====== RSS FEED ======
+
+// HasFeed reports whether a feed URL is configured.
+func (b *BinanceClient) HasFeed() bool {
+	return b.cfg.FeedURL != ""
+}
+
+// GetFeed returns the items of the configured RSS feed (ClientConfig.FeedURL). Feed items carry
+// no numeric ID; Code is taken from the announcement link so they can be
+// matched with CMS articles. CatalogID is unknown and left 0. The feed is
+// a third party, so it gets none of the Binance headers.
+func (b *BinanceClient) GetFeed() ([]NewsArticle, error) {
+	if b.cfg.FeedURL == "" {
+		return nil, ErrNoFeed
+	}
+
+	var feed rssFeed
+	header := http.Header{"Accept": {"application/rss+xml, application/xml"}}
+	err := b.send(b.cfg.FeedURL, b.cfg.NewsTimeout, header, func(r io.Reader) error {
+		if err := xml.NewDecoder(r).Decode(&feed); err != nil {
+			return fmt.Errorf("decoding feed XML: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching announcement feed: %w", err)
+	}
+
+	articles := make([]NewsArticle, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		link := strings.TrimSpace(item.Link)
+		if link == "" {
+			link = strings.TrimSpace(item.GUID)
+		}
+		a := NewsArticle{
+			Code:  path.Base(strings.TrimRight(link, "/")),
+			Title: strings.TrimSpace(item.Title),
+			URL:   link,
+		}
+		if t, ok := parsePubDate(item.PubDate); ok {
+			a.ReleaseDate = t.UnixMilli()
+		}
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+func parsePubDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
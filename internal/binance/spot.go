@@ -0,0 +1,151 @@
+package binance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"bingxGo/internal/parser"
+)
+
+// ====== TYPES ======
+
+// SpotSymbol is a spot symbol from /api/v3/exchangeInfo.
+type SpotSymbol struct {
+	Symbol                 string     `json:"symbol"`
+	Status                 string     `json:"status"` // TRADING, BREAK, HALT, ...
+	BaseAsset              string     `json:"baseAsset"`
+	QuoteAsset             string     `json:"quoteAsset"`
+	IsSpotTradingAllowed   bool       `json:"isSpotTradingAllowed"`
+	IsMarginTradingAllowed bool       `json:"isMarginTradingAllowed"`
+	Permissions            []string   `json:"permissions"`
+	PermissionSets         [][]string `json:"permissionSets"`
+}
+
+type spotExchangeInfoResponse struct {
+	Symbols []SpotSymbol `json:"symbols"`
+}
+
+// HasPermission reports whether the symbol carries perm, e.g. "MARGIN".
+// Binance moved permissions into permissionSets; both are checked.
+func (s SpotSymbol) HasPermission(perm string) bool {
+	for _, p := range s.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	for _, set := range s.PermissionSets {
+		for _, p := range set {
+			if p == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MarketIndex answers where a token or symbol currently trades. Only
+// TRADING symbols are indexed.
+type MarketIndex struct {
+	symbols map[string][]parser.Market // "ABCUSDT" -> markets
+	assets  map[string][]parser.Market // "ABC" -> markets of every pair with that base
+	mu      sync.RWMutex
+}
+
+// ====== FETCH SPOT ======
+
+// FetchSpotExchangeInfo returns every spot symbol in any state.
+func (b *BinanceClient) FetchSpotExchangeInfo() ([]SpotSymbol, error) {
+	var data spotExchangeInfoResponse
+	if err := b.getJSON(b.cfg.SpotURL+"/api/v3/exchangeInfo", b.cfg.ExchangeInfoTimeout, &data); err != nil {
+		return nil, fmt.Errorf("fetching spot exchange info: %w", err)
+	}
+	return data.Symbols, nil
+}
+
+// FetchMarketIndex builds an index from spot and futures exchangeInfo.
+func (b *BinanceClient) FetchMarketIndex() (*MarketIndex, error) {
+	idx := NewMarketIndex()
+	if err := b.RefreshMarketIndex(idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// RefreshMarketIndex refetches both markets into idx.
+func (b *BinanceClient) RefreshMarketIndex(idx *MarketIndex) error {
+	spot, err := b.FetchSpotExchangeInfo()
+	if err != nil {
+		return err
+	}
+	futures, err := b.FetchExchangeInfo()
+	if err != nil {
+		return err
+	}
+	idx.Update(spot, futures)
+	return nil
+}
+
+// ====== MARKET INDEX ======
+
+func NewMarketIndex() *MarketIndex {
+	return &MarketIndex{
+		symbols: make(map[string][]parser.Market),
+		assets:  make(map[string][]parser.Market),
+	}
+}
+
+// Update replaces the index contents.
+func (idx *MarketIndex) Update(spot []SpotSymbol, futures []BinancePair) {
+	symbols := make(map[string][]parser.Market, len(spot)+len(futures))
+	assets := make(map[string][]parser.Market)
+	add := func(symbol, asset string, m parser.Market) {
+		symbols[symbol] = addMarket(symbols[symbol], m)
+		assets[asset] = addMarket(assets[asset], m)
+	}
+
+	for _, s := range spot {
+		if s.Status != StatusTrading {
+			continue
+		}
+		if s.IsSpotTradingAllowed || s.HasPermission("SPOT") {
+			add(s.Symbol, s.BaseAsset, parser.MarketSpot)
+		}
+		if s.IsMarginTradingAllowed || s.HasPermission("MARGIN") {
+			add(s.Symbol, s.BaseAsset, parser.MarketMargin)
+		}
+	}
+	for _, f := range futures {
+		if f.Status != StatusTrading {
+			continue
+		}
+		add(f.Symbol, f.BaseAsset, parser.MarketFutures)
+	}
+
+	idx.mu.Lock()
+	idx.symbols, idx.assets = symbols, assets
+	idx.mu.Unlock()
+}
+
+// Lookup returns the markets where token trades. It accepts a symbol
+// ("ABCUSDT", "ABC/USDT", "ABC-USDT") or a bare asset ("ABC"), and returns
+// nil when it trades nowhere.
+func (idx *MarketIndex) Lookup(token string) []parser.Market {
+	key := strings.ToUpper(strings.NewReplacer("/", "", "-", "").Replace(strings.TrimSpace(token)))
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if m, ok := idx.symbols[key]; ok {
+		return slices.Clone(m)
+	}
+	return slices.Clone(idx.assets[key])
+}
+
+func addMarket(ms []parser.Market, m parser.Market) []parser.Market {
+	if slices.Contains(ms, m) {
+		return ms
+	}
+	return append(ms, m)
+}
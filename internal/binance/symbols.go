@@ -0,0 +1,139 @@
+package binance
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ====== TYPES ======
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeStatus   ChangeKind = "status"
+	ChangeDelivery ChangeKind = "delivery"
+)
+
+// SymbolChange describes one difference between two exchangeInfo
+// snapshots. Old is zero for added symbols, New for removed ones.
+type SymbolChange struct {
+	Symbol     string
+	Kind       ChangeKind
+	Old        BinancePair
+	New        BinancePair
+	DetectedAt time.Time
+}
+
+// Delisting reports whether the change signals that trading is ending: a
+// perpetual got a real delivery date, or the symbol left TRADING or
+// disappeared.
+func (c SymbolChange) Delisting() bool {
+	switch c.Kind {
+	case ChangeRemoved:
+		return c.Old.Status == StatusTrading
+	case ChangeStatus:
+		return c.Old.Status == StatusTrading
+	case ChangeDelivery:
+		return c.New.DeliveryScheduled()
+	}
+	return false
+}
+
+// SymbolDetector diffs successive exchangeInfo snapshots. A status or
+// deliveryDate change is often the earliest machine-readable delisting
+// signal, ahead of the announcement.
+type SymbolDetector struct {
+	prev map[string]BinancePair
+	mu   sync.Mutex
+}
+
+// ====== CONSTRUCTOR ======
+
+func NewSymbolDetector() *SymbolDetector {
+	return &SymbolDetector{}
+}
+
+// ====== DIFF ======
+
+// Diff compares symbols with the previous snapshot and stores them as the
+// new baseline. The first call only records the baseline.
+func (d *SymbolDetector) Diff(symbols []BinancePair) []SymbolChange {
+	next := make(map[string]BinancePair, len(symbols))
+	for _, s := range symbols {
+		next[s.Symbol] = s
+	}
+
+	d.mu.Lock()
+	prev := d.prev
+	d.prev = next
+	d.mu.Unlock()
+
+	if prev == nil {
+		return nil
+	}
+
+	now := time.Now()
+	var changes []SymbolChange
+	for sym, cur := range next {
+		old, ok := prev[sym]
+		if !ok {
+			changes = append(changes, SymbolChange{Symbol: sym, Kind: ChangeAdded, New: cur, DetectedAt: now})
+			continue
+		}
+		if old.Status != cur.Status {
+			changes = append(changes, SymbolChange{Symbol: sym, Kind: ChangeStatus, Old: old, New: cur, DetectedAt: now})
+		}
+		if old.DeliveryDate != cur.DeliveryDate {
+			changes = append(changes, SymbolChange{Symbol: sym, Kind: ChangeDelivery, Old: old, New: cur, DetectedAt: now})
+		}
+	}
+	for sym, old := range prev {
+		if _, ok := next[sym]; !ok {
+			changes = append(changes, SymbolChange{Symbol: sym, Kind: ChangeRemoved, Old: old, DetectedAt: now})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Symbol != changes[j].Symbol {
+			return changes[i].Symbol < changes[j].Symbol
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// ====== POLLING ======
+
+// WatchSymbols fetches exchangeInfo every interval until ctx is cancelled
+// and passes each change to onChange.
+func (b *BinanceClient) WatchSymbols(ctx context.Context, interval time.Duration, onChange func(SymbolChange)) error {
+	d := NewSymbolDetector()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		symbols, err := b.FetchExchangeInfo()
+		switch {
+		case err != nil:
+			log.Printf("Binance exchange info poll failed: %v", err)
+		case len(symbols) == 0:
+			// An empty snapshot would read as every symbol removed.
+			log.Println("Binance exchange info returned no symbols, skipping")
+		default:
+			for _, c := range d.Diff(symbols) {
+				onChange(c)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package binance
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"bingxGo/internal/parser"
+)
+
+// ====== BODY TO TEXT ======
+
+// cmsNode is one node of the CMS rich-text tree:
+// {"node":"element","tag":"p","child":[{"node":"text","text":"..."}]}.
+type cmsNode struct {
+	Node  string    `json:"node"`
+	Tag   string    `json:"tag"`
+	Text  string    `json:"text"`
+	Child []cmsNode `json:"child"`
+}
+
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "table": true,
+	"ul": true, "ol": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "section": true, "blockquote": true,
+}
+
+var (
+	htmlTag    = regexp.MustCompile(`(?s)<(/?)([a-zA-Z0-9]+)[^>]*>`)
+	htmlDrop   = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	blankLines = regexp.MustCompile(`\n{2,}`)
+	spaceRun   = regexp.MustCompile(`[ \t\x{00a0}]+`)
+)
+
+// Text returns the article body as plain text, one paragraph per line.
+func (a *Article) Text() string {
+	return BodyText(a.Body)
+}
+
+// BodyText converts a CMS body to plain text. It handles the JSON
+// rich-text tree of current posts and the HTML of older ones.
+func BodyText(body string) string {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	var root cmsNode
+	if strings.HasPrefix(body, "{") && json.Unmarshal([]byte(body), &root) == nil {
+		writeNode(&sb, root)
+	} else {
+		writeHTML(&sb, body)
+	}
+	return tidy(sb.String())
+}
+
+func writeNode(sb *strings.Builder, n cmsNode) {
+	if n.Node == "text" {
+		sb.WriteString(html.UnescapeString(n.Text))
+		return
+	}
+
+	tag := strings.ToLower(n.Tag)
+	block := blockTags[tag]
+	if block {
+		sb.WriteByte('\n')
+	}
+	if tag == "li" {
+		sb.WriteString("- ")
+	}
+	for i, c := range n.Child {
+		if tag == "tr" && i > 0 {
+			sb.WriteString(" | ")
+		}
+		writeNode(sb, c)
+	}
+	if block {
+		sb.WriteByte('\n')
+	}
+}
+
+func writeHTML(sb *strings.Builder, s string) {
+	s = htmlDrop.ReplaceAllString(s, "")
+
+	last := 0
+	cells := 0
+	for _, m := range htmlTag.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(html.UnescapeString(s[last:m[0]]))
+		last = m[1]
+
+		closing := m[3] > m[2]
+		tag := strings.ToLower(s[m[4]:m[5]])
+		switch {
+		case tag == "td" || tag == "th":
+			if !closing {
+				if cells > 0 {
+					sb.WriteString(" | ")
+				}
+				cells++
+			}
+		case blockTags[tag]:
+			sb.WriteByte('\n')
+			if tag == "tr" {
+				cells = 0
+			}
+			if tag == "li" && !closing {
+				sb.WriteString("- ")
+			}
+		}
+	}
+	sb.WriteString(html.UnescapeString(s[last:]))
+}
+
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(l, " "))
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n"))
+}
+
+// ====== SECTIONS ======
+
+// ArticleDetails holds what an announcement body says beyond its title.
+// Sections are the paragraphs a fact was found in, for display and audit.
+type ArticleDetails struct {
+	Pairs        []string // Binance symbols ("ABCUSDT", also for "ABC/USDT"), in order of appearance
+	PairSections []string
+
+	DelistTimes    []time.Time // UTC
+	DelistSections []string
+
+	SettlementTimes    []time.Time // UTC, futures close-out and settlement
+	SettlementSections []string
+}
+
+var (
+	bodyPair = regexp.MustCompile(`\b([A-Z0-9]{2,15})/(USDT|USDC|FDUSD|BUSD|TUSD|BTC|ETH|BNB|TRY|EUR|BRL|JPY|DAI)\b|\b([A-Z0-9]{2,15}(?:USDT|USDC))\b`)
+	// "2024-10-04 03:00 (UTC)", "2024-10-04 at 03:00 UTC", "2024/10/04 03:00:00 (UTC)"
+	bodyTime = regexp.MustCompile(`(\d{4})[-/](\d{2})[-/](\d{2})(?:\s+at)?\s+(\d{1,2}):(\d{2})(?::(\d{2}))?\s*\(?UTC(?:\+0)?\)?`)
+
+	delistWords     = regexp.MustCompile(`(?i)delist|cease\s+trading|remov(e|al)|halt|suspend`)
+	settlementWords = regexp.MustCompile(`(?i)\bsettle(d|ment)?\b|close\s+(all\s+|any\s+)?(open\s+)?positions`)
+)
+
+// Details returns the pairs and times mentioned in the article body.
+func (a *Article) Details() ArticleDetails {
+	return ExtractDetails(a.Text())
+}
+
+// ExtractDetails scans plain text (see BodyText) paragraph by paragraph.
+// Times in a paragraph about futures settlement count as settlement times,
+// times in a paragraph about delisting as delisting times; a paragraph
+// about both feeds both lists.
+func ExtractDetails(text string) ArticleDetails {
+	var d ArticleDetails
+	seenPair := make(map[string]bool)
+	seenDelist := make(map[time.Time]bool)
+	seenSettle := make(map[time.Time]bool)
+
+	for _, para := range strings.Split(text, "\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+
+		pairs := bodyPairs(para)
+		if len(pairs) > 0 {
+			d.PairSections = append(d.PairSections, para)
+			for _, p := range pairs {
+				if !seenPair[p] {
+					seenPair[p] = true
+					d.Pairs = append(d.Pairs, p)
+				}
+			}
+		}
+
+		times := bodyTimes(para)
+		if len(times) == 0 {
+			continue
+		}
+		if settlementWords.MatchString(para) {
+			d.SettlementSections = append(d.SettlementSections, para)
+			d.SettlementTimes = appendTimes(d.SettlementTimes, seenSettle, times)
+		}
+		if delistWords.MatchString(para) {
+			d.DelistSections = append(d.DelistSections, para)
+			d.DelistTimes = appendTimes(d.DelistTimes, seenDelist, times)
+		}
+	}
+
+	sortTimes(d.DelistTimes)
+	sortTimes(d.SettlementTimes)
+	return d
+}
+
+func bodyPairs(s string) []string {
+	var pairs []string
+	for _, m := range bodyPair.FindAllStringSubmatch(s, -1) {
+		if m[1] != "" {
+			pairs = append(pairs, parser.Symbol(m[1], m[2]))
+		} else {
+			pairs = append(pairs, m[3])
+		}
+	}
+	return pairs
+}
+
+func bodyTimes(s string) []time.Time {
+	var times []time.Time
+	for _, m := range bodyTime.FindAllStringSubmatch(s, -1) {
+		sec := m[6]
+		if sec == "" {
+			sec = "00"
+		}
+		t, err := time.Parse("2006-01-02 15:04:05",
+			m[1]+"-"+m[2]+"-"+m[3]+" "+pad2(m[4])+":"+m[5]+":"+sec)
+		if err == nil {
+			times = append(times, t.UTC())
+		}
+	}
+	return times
+}
+
+func pad2(s string) string {
+	if len(s) == 1 {
+		return "0" + s
+	}
+	return s
+}
+
+func appendTimes(dst []time.Time, seen map[time.Time]bool, times []time.Time) []time.Time {
+	for _, t := range times {
+		if !seen[t] {
+			seen[t] = true
+			dst = append(dst, t)
+		}
+	}
+	return dst
+}
+
+func sortTimes(ts []time.Time) {
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Before(ts[j]) })
+}
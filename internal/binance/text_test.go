@@ -0,0 +1,63 @@
+package binance
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// cmsBody is a trimmed CMS rich-text body in the shape the detail API
+// returns for current delisting posts.
+const cmsBody = `{"node":"root","child":[
+ {"node":"element","tag":"p","child":[{"node":"text","text":"Fellow Binancians,"}]},
+ {"node":"element","tag":"p","child":[{"node":"text","text":"Binance will delist and cease trading on all spot trading pairs for the following tokens at 2024-10-04 03:00 (UTC): ABC, XYZ"}]},
+ {"node":"element","tag":"p","child":[{"node":"text","text":"The exact trading pairs being removed are: ABC/BTC, ABC/USDT, XYZ/USDT"}]},
+ {"node":"element","tag":"p","child":[{"node":"text","text":"Binance Futures will close all positions and conduct an automatic settlement on the ABCUSDT perpetual contract at 2024-10-03 09:00 (UTC)."}]},
+ {"node":"element","tag":"p","child":[{"node":"text","text":"Binance Futures launched the ABCUSDT perpetual contract on 2023-01-05 08:00 (UTC)."}]}
+]}`
+
+// htmlBody is an older HTML post where one paragraph carries both the
+// futures settlement and the delisting.
+const htmlBody = `<div><p>Fellow Binancians,</p>
+<p>Binance Futures will delist the <strong>DEFUSDT</strong> perpetual contract and settle all open positions at 2023/06/12 at 08:00 UTC.</p>
+<table><tr><th>Pair</th><th>Removal</th></tr><tr><td>DEF/USDT</td><td>Delist at 2023-06-13 03:00 (UTC)</td></tr></table>
+<script>var ignored = "GHI/USDT 2023-06-14 03:00 (UTC)";</script>
+</div>`
+
+func utc(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestExtractDetailsCMS(t *testing.T) {
+	d := ExtractDetails(BodyText(cmsBody))
+
+	if want := []string{"ABCBTC", "ABCUSDT", "XYZUSDT"}; !reflect.DeepEqual(d.Pairs, want) {
+		t.Errorf("Pairs = %v, want %v", d.Pairs, want)
+	}
+	if want := []time.Time{utc("2024-10-04 03:00")}; !reflect.DeepEqual(d.DelistTimes, want) {
+		t.Errorf("DelistTimes = %v, want %v", d.DelistTimes, want)
+	}
+	// The launch date mentions a perpetual contract but no settlement.
+	if want := []time.Time{utc("2024-10-03 09:00")}; !reflect.DeepEqual(d.SettlementTimes, want) {
+		t.Errorf("SettlementTimes = %v, want %v", d.SettlementTimes, want)
+	}
+}
+
+func TestExtractDetailsHTML(t *testing.T) {
+	text := BodyText(htmlBody)
+	d := ExtractDetails(text)
+
+	if want := []string{"DEFUSDT"}; !reflect.DeepEqual(d.Pairs, want) {
+		t.Errorf("Pairs = %v, want %v\ntext:\n%s", d.Pairs, want, text)
+	}
+	if want := []time.Time{utc("2023-06-12 08:00"), utc("2023-06-13 03:00")}; !reflect.DeepEqual(d.DelistTimes, want) {
+		t.Errorf("DelistTimes = %v, want %v\ntext:\n%s", d.DelistTimes, want, text)
+	}
+	if want := []time.Time{utc("2023-06-12 08:00")}; !reflect.DeepEqual(d.SettlementTimes, want) {
+		t.Errorf("SettlementTimes = %v, want %v", d.SettlementTimes, want)
+	}
+}
@@ -0,0 +1,426 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+
+	"bingxGo/internal/websocket"
+)
+
+// ====== CONSTANTS ======
+
+const (
+	futuresStreamURL = "wss://fstream.binance.com/stream"
+
+	// Binance drops every connection after 24h; reconnect a bit earlier so
+	// the switch happens on our schedule.
+	maxConnLifetime = 23*time.Hour + 30*time.Minute
+
+	// A replacement that sends nothing for this long takes over anyway.
+	rolloverTimeout = 30 * time.Second
+
+	// Binance accepts at most 200 streams on one connection.
+	maxStreamsPerConn = 200
+)
+
+// ====== STREAM NAMES ======
+
+func MarkPriceStream(symbol string) string {
+	return strings.ToLower(symbol) + "@markPrice@1s"
+}
+
+func AggTradeStream(symbol string) string {
+	return strings.ToLower(symbol) + "@aggTrade"
+}
+
+func BookTickerStream(symbol string) string {
+	return strings.ToLower(symbol) + "@bookTicker"
+}
+
+// ====== TYPES ======
+
+// MarketStream is a USDⓈ-M futures market-data client over a combined
+// stream. Events use the same types as the BingX client; symbols keep the
+// Binance form ("BTCUSDT").
+type MarketStream struct {
+	url         string
+	streams     []string
+	handlers    websocket.Handlers
+	maxLifetime time.Duration
+	emitMu      sync.Mutex // connections overlap during rollover
+
+	// lastTrade is the newest aggregate trade ID per symbol, so trades a
+	// replacement connection repeats are dropped. Guarded by emitMu.
+	lastTrade map[string]int64
+}
+
+type combinedMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Binance keys differ only by case ("p"/"P", "a"/"A"), and encoding/json
+// matches case-insensitively, so each event type declares every key it
+// carries.
+type markPricePayload struct {
+	Event       string `json:"e"`
+	EventTime   int64  `json:"E"`
+	Symbol      string `json:"s"`
+	MarkPrice   string `json:"p"`
+	SettlePrice string `json:"P"`
+	IndexPrice  string `json:"i"`
+}
+
+type aggTradePayload struct {
+	Event      string `json:"e"`
+	EventTime  int64  `json:"E"`
+	Symbol     string `json:"s"`
+	AggID      int64  `json:"a"`
+	Price      string `json:"p"`
+	Quantity   string `json:"q"`
+	FirstID    int64  `json:"f"`
+	LastID     int64  `json:"l"`
+	TradeTime  int64  `json:"T"`
+	BuyerMaker bool   `json:"m"`
+}
+
+type bookTickerPayload struct {
+	Event     string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	BidPrice  string `json:"b"`
+	BidQty    string `json:"B"`
+	AskPrice  string `json:"a"`
+	AskQty    string `json:"A"`
+}
+
+// ====== CONSTRUCTOR ======
+
+// NewMarketStream creates a client for the given stream names, e.g.
+// MarkPriceStream("BTCUSDT").
+func NewMarketStream(streams []string, handlers websocket.Handlers) *MarketStream {
+	return &MarketStream{
+		url:         futuresStreamURL,
+		streams:     streams,
+		handlers:    handlers,
+		maxLifetime: maxConnLifetime,
+		lastTrade:   make(map[string]int64),
+	}
+}
+
+// ====== CONNECTION ======
+
+// Run keeps every stream connected until ctx is cancelled. Streams are
+// split over connections of at most maxStreamsPerConn; each reconnects on
+// errors with backoff and rolls over before Binance's 24h forced
+// disconnect.
+func (m *MarketStream) Run(ctx context.Context) error {
+	if len(m.streams) == 0 {
+		return fmt.Errorf("no Binance streams to subscribe")
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(m.streams); start += maxStreamsPerConn {
+		end := min(start+maxStreamsPerConn, len(m.streams))
+		wg.Add(1)
+		go func(streams []string) {
+			defer wg.Done()
+			m.runConn(ctx, streams)
+		}(m.streams[start:end])
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// link is a connection whose messages are being read.
+type link struct {
+	conn    *gws.Conn
+	done    chan error  // read error once the reader stops
+	retired atomic.Bool // a replacement delivered; drop further messages
+}
+
+// runConn serves one connection's streams until ctx ends.
+func (m *MarketStream) runConn(ctx context.Context, streams []string) {
+	backoff := time.Second
+	var cur *link
+	for {
+		if cur == nil {
+			conn, err := m.dial(ctx, streams)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Binance stream error: %v", err)
+				if !sleep(ctx, backoff) {
+					return
+				}
+				backoff = min(backoff*2, time.Minute)
+				continue
+			}
+			cur = m.start(conn, nil)
+		}
+
+		started := time.Now()
+		timer := time.NewTimer(m.maxLifetime)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			cur.conn.Close()
+			<-cur.done
+			return
+
+		case err := <-cur.done:
+			timer.Stop()
+			cur.conn.Close()
+			cur = nil
+			log.Printf("Binance stream error: %v", err)
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, time.Minute)
+
+		case <-timer.C:
+			log.Println("Binance connection lifetime reached, rolling over")
+			cur = m.rollover(ctx, streams, cur)
+			backoff = time.Second
+		}
+	}
+}
+
+// rollover dials a replacement while old keeps delivering and retires old
+// once the new connection sends its first message, so there is no gap.
+// It returns nil when old fails before a replacement is up.
+func (m *MarketStream) rollover(ctx context.Context, streams []string, old *link) *link {
+	oldDone := old.done
+	retire := func() {
+		old.conn.Close()
+		if oldDone != nil {
+			<-oldDone
+		}
+	}
+
+	for {
+		conn, err := m.dial(ctx, streams)
+		if err == nil {
+			handed := make(chan struct{})
+			next := m.start(conn, func() {
+				old.retired.Store(true)
+				close(handed)
+			})
+
+			select {
+			case <-handed:
+				retire()
+				return next
+			case <-time.After(rolloverTimeout):
+				log.Println("Binance replacement connection silent, switching anyway")
+				retire()
+				return next
+			case <-oldDone:
+				old.conn.Close()
+				return next
+			case err := <-next.done:
+				conn.Close()
+				log.Printf("Binance replacement connection failed: %v", err)
+			case <-ctx.Done():
+				conn.Close()
+				<-next.done
+				retire()
+				return nil
+			}
+		} else {
+			log.Printf("Binance replacement dial failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			retire()
+			return nil
+		case <-oldDone:
+			old.conn.Close()
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (m *MarketStream) dial(ctx context.Context, streams []string) (*gws.Conn, error) {
+	url := m.url + "?streams=" + strings.Join(streams, "/")
+	conn, _, err := gws.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Binance WebSocket connection failed: %w", err)
+	}
+	log.Printf("✅ Binance WebSocket connected (%d streams)", len(streams))
+	return conn, nil
+}
+
+// start reads conn in the background, calling onFirst before the first
+// message is handled.
+func (m *MarketStream) start(conn *gws.Conn, onFirst func()) *link {
+	l := &link{conn: conn, done: make(chan error, 1)}
+	go func() {
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				l.done <- fmt.Errorf("read: %w", err)
+				return
+			}
+			if onFirst != nil {
+				onFirst()
+				onFirst = nil
+			}
+			if l.retired.Load() {
+				continue
+			}
+			m.handleMessage(l, msg)
+		}
+	}()
+	return l
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// ====== MESSAGE HANDLING ======
+
+// handleMessage decodes msg from l and emits it unless l has been retired
+// or the trade was already delivered by the other connection.
+func (m *MarketStream) handleMessage(l *link, msg []byte) {
+	var cm combinedMessage
+	if err := json.Unmarshal(msg, &cm); err != nil {
+		log.Printf("Binance JSON decode failed: %v", err)
+		return
+	}
+	if len(cm.Data) == 0 {
+		return
+	}
+
+	var head struct {
+		Event     string `json:"e"`
+		EventTime int64  `json:"E"`
+	}
+	if err := json.Unmarshal(cm.Data, &head); err != nil {
+		log.Printf("Binance %s decode failed: %v", cm.Stream, err)
+		return
+	}
+
+	ev, aggID, err := toEvent(head.Event, cm.Data)
+	if err != nil {
+		log.Printf("Invalid Binance %s message: %v", cm.Stream, err)
+		return
+	}
+	if ev.Kind == "" {
+		return
+	}
+
+	m.emitMu.Lock()
+	defer m.emitMu.Unlock()
+	// Checked again under the lock: the replacement's first message may
+	// have been emitted while this one was decoding.
+	if l.retired.Load() {
+		return
+	}
+	if aggID != 0 {
+		if aggID <= m.lastTrade[ev.Symbol] {
+			return
+		}
+		m.lastTrade[ev.Symbol] = aggID
+	}
+	m.handlers.Handle(ev)
+}
+
+// toEvent converts a Binance payload into a shared market event. Unknown
+// event types yield an empty event. aggID is the aggregate trade ID of a
+// trade, 0 otherwise.
+func toEvent(event string, data json.RawMessage) (ev websocket.Event, aggID int64, err error) {
+	switch event {
+	case "markPriceUpdate":
+		var p markPricePayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return websocket.Event{}, 0, err
+		}
+		price, err := strconv.ParseFloat(p.MarkPrice, 64)
+		if err != nil {
+			return websocket.Event{}, 0, fmt.Errorf("mark price %s: %w", p.MarkPrice, err)
+		}
+		return websocket.Event{
+			Kind:   websocket.StreamPrice,
+			Symbol: p.Symbol,
+			Price: websocket.PriceUpdate{
+				Type:   "priceUpdate",
+				Stream: "markPrice",
+				Symbol: p.Symbol,
+				Price:  price,
+			},
+		}, 0, nil
+
+	case "aggTrade":
+		var p aggTradePayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return websocket.Event{}, 0, err
+		}
+		price, err := strconv.ParseFloat(p.Price, 64)
+		if err != nil {
+			return websocket.Event{}, 0, fmt.Errorf("trade price %s: %w", p.Price, err)
+		}
+		qty, _ := strconv.ParseFloat(p.Quantity, 64)
+		return websocket.Event{
+			Kind:   websocket.StreamTrade,
+			Symbol: p.Symbol,
+			Trade: &websocket.Trade{
+				Symbol:     p.Symbol,
+				Price:      price,
+				Quantity:   qty,
+				BuyerMaker: p.BuyerMaker,
+				Time:       p.TradeTime,
+			},
+		}, p.AggID, nil
+
+	case "bookTicker":
+		var p bookTickerPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return websocket.Event{}, 0, err
+		}
+		bid, err := strconv.ParseFloat(p.BidPrice, 64)
+		if err != nil {
+			return websocket.Event{}, 0, fmt.Errorf("bid price %s: %w", p.BidPrice, err)
+		}
+		ask, err := strconv.ParseFloat(p.AskPrice, 64)
+		if err != nil {
+			return websocket.Event{}, 0, fmt.Errorf("ask price %s: %w", p.AskPrice, err)
+		}
+		bidQty, _ := strconv.ParseFloat(p.BidQty, 64)
+		askQty, _ := strconv.ParseFloat(p.AskQty, 64)
+		return websocket.Event{
+			Kind:   websocket.StreamBookTicker,
+			Symbol: p.Symbol,
+			BookTicker: &websocket.BookTicker{
+				Symbol:   p.Symbol,
+				BidPrice: bid,
+				BidQty:   bidQty,
+				AskPrice: ask,
+				AskQty:   askQty,
+				Time:     p.EventTime,
+			},
+		}, 0, nil
+	}
+	return websocket.Event{}, 0, nil
+}
@@ -0,0 +1,44 @@
+package binance
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bingxGo/internal/websocket"
+)
+
+func aggTradeMsg(id int64, price string) []byte {
+	return []byte(fmt.Sprintf(`{"stream":"btcusdt@aggTrade","data":{"e":"aggTrade","E":1,"s":"BTCUSDT","a":%d,"p":"%s","q":"1","f":1,"l":1,"T":1,"m":false}}`, id, price))
+}
+
+func markPriceMsg(price string) []byte {
+	return []byte(`{"stream":"btcusdt@markPrice@1s","data":{"e":"markPriceUpdate","E":1,"s":"BTCUSDT","p":"` + price + `","P":"0","i":"0"}}`)
+}
+
+func TestRolloverDropsRetiredAndRepeatedEvents(t *testing.T) {
+	var got []string
+	m := NewMarketStream(nil, websocket.Handlers{OnEvent: func(ev websocket.Event) {
+		switch ev.Kind {
+		case websocket.StreamTrade:
+			got = append(got, fmt.Sprintf("trade %g", ev.Trade.Price))
+		case websocket.StreamPrice:
+			got = append(got, fmt.Sprintf("mark %g", ev.Price.Price))
+		}
+	}})
+	old, next := &link{}, &link{}
+
+	m.handleMessage(old, aggTradeMsg(10, "1"))
+	m.handleMessage(old, aggTradeMsg(11, "2"))
+	// The replacement starts a little behind the old connection.
+	old.retired.Store(true)
+	m.handleMessage(next, aggTradeMsg(11, "2"))
+	m.handleMessage(next, markPriceMsg("100"))
+	m.handleMessage(old, aggTradeMsg(12, "3")) // decoded before it saw retired
+	m.handleMessage(next, aggTradeMsg(12, "3"))
+
+	want := []string{"trade 1", "trade 2", "mark 100", "trade 3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("emitted %v, want %v", got, want)
+	}
+}
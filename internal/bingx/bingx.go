@@ -138,6 +138,19 @@ type PriceItem struct {
 	NextFundingTime, Time                                                              int64
 }
 
+type DepthResponse struct {
+	Code int       `json:"code"`
+	Msg  string    `json:"msg"`
+	Data DepthData `json:"data"`
+}
+
+type DepthData struct {
+	Time         int64      `json:"T"`
+	LastUpdateID int64      `json:"lastUpdateId"` // 0 when the endpoint omits it
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
 type LeverageResponse struct {
 	Code int          `json:"code"`
 	Msg  string       `json:"msg"`
@@ -202,6 +215,26 @@ func FetchPrices() (*PricesResponse, error) {
 	return &res, nil
 }
 
+func FetchDepth(symbol string, limit int) (*DepthResponse, error) {
+	params := map[string]string{
+		"symbol": symbol,
+		"limit":  strconv.Itoa(limit),
+	}
+	url := fmt.Sprintf("%s/openApi/swap/v2/quote/depth?%s", baseURL, buildQuery(params))
+
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	var res DepthResponse
+	if err := doRequest(req, &res); err != nil {
+		return nil, err
+	}
+	if res.Code != 0 {
+		return nil, fmt.Errorf("%s API error: %s", ts(), res.Msg)
+	}
+	return &res, nil
+}
+
 func FetchLeverage(apiKey, apiSecret, symbol string) (*LeverageResponse, error) {
 	params := map[string]string{
 		"symbol":    symbol,
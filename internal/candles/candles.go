@@ -0,0 +1,273 @@
+package candles
+
+import (
+	"sync"
+	"time"
+
+	"bingxGo/internal/websocket"
+)
+
+// ====== TYPES ======
+
+type Candle struct {
+	Symbol   string
+	Interval time.Duration
+	OpenTime time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+	Trades   int
+}
+
+// CloseTime returns the end of the candle's interval.
+func (c Candle) CloseTime() time.Time {
+	return c.OpenTime.Add(c.Interval)
+}
+
+type seriesKey struct {
+	symbol   string
+	interval time.Duration
+}
+
+// series holds the open candle and a ring buffer of closed ones.
+type series struct {
+	current    *Candle
+	firstAt    time.Time // times of the current candle's earliest and latest ticks
+	lastAt     time.Time
+	lastClosed time.Time // OpenTime of the last emitted candle
+	ring       []Candle
+	next       int
+	full       bool
+}
+
+// Aggregator builds OHLCV candles for several intervals from price and
+// trade events.
+type Aggregator struct {
+	intervals   []time.Duration
+	size        int
+	series      map[seriesKey]*series
+	onClose     func(Candle)
+	priceStream string // the PriceUpdate.Stream HandlePrice uses
+	mu          sync.Mutex
+}
+
+// defaultPriceStream is the price stream candles are built from unless
+// SetPriceStream picks another.
+const defaultPriceStream = "lastPrice"
+
+// ====== CONSTRUCTOR ======
+
+// NewAggregator keeps up to size closed candles per symbol and interval and
+// calls onClose (if set) for every candle that closes.
+//
+// Example: agg := candles.NewAggregator([]time.Duration{time.Minute, 5 * time.Minute}, 500, onClose)
+func NewAggregator(intervals []time.Duration, size int, onClose func(Candle)) *Aggregator {
+	if size <= 0 {
+		size = 500
+	}
+	return &Aggregator{
+		intervals:   intervals,
+		size:        size,
+		series:      make(map[seriesKey]*series),
+		onClose:     onClose,
+		priceStream: defaultPriceStream,
+	}
+}
+
+// SetPriceStream selects the price stream HandlePrice builds candles from,
+// "lastPrice" (the default) or "markPrice". Mixing both would put two
+// different prices into one series.
+func (a *Aggregator) SetPriceStream(stream string) {
+	a.mu.Lock()
+	a.priceStream = stream
+	a.mu.Unlock()
+}
+
+// ====== INPUT ======
+
+// HandlePrice consumes a price update; use it as websocket.Handlers.OnPrice.
+// Updates of other streams than the selected one are ignored. Price
+// updates carry no volume or time, so they move OHLC at the time of
+// receipt without counting as trades.
+func (a *Aggregator) HandlePrice(u websocket.PriceUpdate) {
+	a.mu.Lock()
+	stream := a.priceStream
+	a.mu.Unlock()
+	if u.Stream != stream {
+		return
+	}
+	a.add(u.Symbol, u.Price, 0, time.Now(), false)
+}
+
+// HandleTrade consumes a trade; use it as websocket.Handlers.OnTrade.
+func (a *Aggregator) HandleTrade(t websocket.Trade) {
+	at := time.Now()
+	if t.Time > 0 {
+		at = time.UnixMilli(t.Time)
+	}
+	a.Add(t.Symbol, t.Price, t.Quantity, at)
+}
+
+// Add records a trade for symbol in every configured interval. Ticks for a
+// candle that was already emitted are dropped; late ticks within the open
+// candle update high, low and volume but not open or close, which follow
+// the earliest and latest tick times.
+func (a *Aggregator) Add(symbol string, price, qty float64, at time.Time) {
+	a.add(symbol, price, qty, at, true)
+}
+
+func (a *Aggregator) add(symbol string, price, qty float64, at time.Time, trade bool) {
+	var closed []Candle
+
+	a.mu.Lock()
+	for _, iv := range a.intervals {
+		s := a.get(symbol, iv)
+		openTime := at.Truncate(iv)
+		if !s.lastClosed.IsZero() && !openTime.After(s.lastClosed) {
+			continue
+		}
+		if s.current != nil && openTime.Before(s.current.OpenTime) {
+			continue
+		}
+
+		if s.current != nil && openTime.After(s.current.OpenTime) {
+			closed = append(closed, a.close(s))
+		}
+
+		if s.current == nil {
+			s.current = &Candle{
+				Symbol:   symbol,
+				Interval: iv,
+				OpenTime: openTime,
+				Open:     price,
+				High:     price,
+				Low:      price,
+				Close:    price,
+			}
+			s.firstAt, s.lastAt = at, at
+		}
+
+		c := s.current
+		if price > c.High {
+			c.High = price
+		}
+		if price < c.Low {
+			c.Low = price
+		}
+		if at.Before(s.firstAt) {
+			c.Open, s.firstAt = price, at
+		}
+		if !at.Before(s.lastAt) {
+			c.Close, s.lastAt = price, at
+		}
+		c.Volume += qty
+		if trade {
+			c.Trades++
+		}
+	}
+	a.mu.Unlock()
+
+	a.emit(closed)
+}
+
+// Flush closes candles whose interval ended before now, so quiet symbols
+// still emit on time. Call it periodically, e.g. once a second.
+func (a *Aggregator) Flush(now time.Time) {
+	var closed []Candle
+
+	a.mu.Lock()
+	for _, s := range a.series {
+		if s.current != nil && !now.Before(s.current.CloseTime()) {
+			closed = append(closed, a.close(s))
+		}
+	}
+	a.mu.Unlock()
+
+	a.emit(closed)
+}
+
+// Run flushes every interval until quit is closed.
+func (a *Aggregator) Run(interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case now := <-ticker.C:
+			a.Flush(now)
+		}
+	}
+}
+
+// ====== QUERIES ======
+
+// Candles returns closed candles for symbol and interval, oldest first.
+func (a *Aggregator) Candles(symbol string, interval time.Duration) []Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.series[seriesKey{symbol, interval}]
+	if !ok {
+		return nil
+	}
+	if !s.full {
+		return append([]Candle(nil), s.ring[:s.next]...)
+	}
+	out := make([]Candle, 0, len(s.ring))
+	out = append(out, s.ring[s.next:]...)
+	return append(out, s.ring[:s.next]...)
+}
+
+// Current returns the candle still being built for symbol and interval.
+func (a *Aggregator) Current(symbol string, interval time.Duration) (Candle, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.series[seriesKey{symbol, interval}]
+	if !ok || s.current == nil {
+		return Candle{}, false
+	}
+	return *s.current, true
+}
+
+// ====== INTERNALS ======
+
+func (a *Aggregator) get(symbol string, interval time.Duration) *series {
+	key := seriesKey{symbol, interval}
+	s, ok := a.series[key]
+	if !ok {
+		s = &series{ring: make([]Candle, a.size)}
+		a.series[key] = s
+	}
+	return s
+}
+
+// close moves the open candle into the ring and returns it.
+func (a *Aggregator) close(s *series) Candle {
+	c := *s.current
+	a.push(s, c)
+	s.lastClosed = c.OpenTime
+	s.current = nil
+	return c
+}
+
+func (a *Aggregator) push(s *series, c Candle) {
+	s.ring[s.next] = c
+	s.next = (s.next + 1) % len(s.ring)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+func (a *Aggregator) emit(closed []Candle) {
+	if a.onClose == nil {
+		return
+	}
+	for _, c := range closed {
+		a.onClose(c)
+	}
+}
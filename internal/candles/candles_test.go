@@ -0,0 +1,103 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"bingxGo/internal/websocket"
+)
+
+var t0 = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func collect(out *[]Candle) func(Candle) {
+	return func(c Candle) { *out = append(*out, c) }
+}
+
+func TestLateTickInOpenCandle(t *testing.T) {
+	a := NewAggregator([]time.Duration{time.Minute}, 10, nil)
+	a.Add("BTC-USDT", 100, 1, t0.Add(10*time.Second))
+	a.Add("BTC-USDT", 102, 1, t0.Add(30*time.Second))
+	a.Add("BTC-USDT", 99, 1, t0.Add(20*time.Second)) // late
+	a.Add("BTC-USDT", 101, 1, t0.Add(5*time.Second)) // late, earliest
+
+	c, ok := a.Current("BTC-USDT", time.Minute)
+	if !ok {
+		t.Fatal("no open candle")
+	}
+	want := Candle{
+		Symbol: "BTC-USDT", Interval: time.Minute, OpenTime: t0,
+		Open: 101, High: 102, Low: 99, Close: 102, Volume: 4, Trades: 4,
+	}
+	if c != want {
+		t.Errorf("candle = %+v, want %+v", c, want)
+	}
+}
+
+func TestLateTickAfterCloseIsDropped(t *testing.T) {
+	var closed []Candle
+	a := NewAggregator([]time.Duration{time.Minute}, 10, collect(&closed))
+
+	a.Add("BTC-USDT", 100, 1, t0.Add(10*time.Second))
+	a.Add("BTC-USDT", 105, 1, t0.Add(70*time.Second)) // closes the first minute
+	a.Add("BTC-USDT", 90, 1, t0.Add(50*time.Second))  // belongs to the emitted candle
+
+	if len(closed) != 1 || closed[0].Low != 100 {
+		t.Fatalf("closed = %+v", closed)
+	}
+	if c, _ := a.Current("BTC-USDT", time.Minute); c.Low != 105 || c.Trades != 1 {
+		t.Errorf("late tick leaked into open candle: %+v", c)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	var closed []Candle
+	a := NewAggregator([]time.Duration{time.Minute}, 10, collect(&closed))
+
+	a.Add("BTC-USDT", 100, 1, t0.Add(10*time.Second))
+	a.Flush(t0.Add(59 * time.Second))
+	if len(closed) != 0 {
+		t.Fatalf("flushed an open candle: %+v", closed)
+	}
+
+	a.Flush(t0.Add(time.Minute))
+	if len(closed) != 1 || !closed[0].OpenTime.Equal(t0) {
+		t.Fatalf("closed = %+v", closed)
+	}
+	if _, ok := a.Current("BTC-USDT", time.Minute); ok {
+		t.Error("candle still open after flush")
+	}
+
+	// A late tick after the flush must not reopen and re-emit the candle.
+	a.Add("BTC-USDT", 99, 1, t0.Add(50*time.Second))
+	a.Flush(t0.Add(2 * time.Minute))
+	if len(closed) != 1 {
+		t.Errorf("candle emitted again: %+v", closed)
+	}
+	if got := a.Candles("BTC-USDT", time.Minute); len(got) != 1 {
+		t.Errorf("history = %+v", got)
+	}
+}
+
+func TestPriceTicksAreNotTrades(t *testing.T) {
+	a := NewAggregator([]time.Duration{time.Hour}, 10, nil)
+	a.HandlePrice(websocket.PriceUpdate{Symbol: "BTC-USDT", Stream: "lastPrice", Price: 100})
+	a.HandlePrice(websocket.PriceUpdate{Symbol: "BTC-USDT", Stream: "lastPrice", Price: 101})
+
+	c, ok := a.Current("BTC-USDT", time.Hour)
+	if !ok || c.Trades != 0 || c.Close != 101 {
+		t.Errorf("candle = %+v", c)
+	}
+}
+
+func TestHandlePriceUsesOneStream(t *testing.T) {
+	a := NewAggregator([]time.Duration{time.Hour}, 10, nil)
+	a.SetPriceStream("markPrice")
+	a.HandlePrice(websocket.PriceUpdate{Symbol: "BTC-USDT", Stream: "markPrice", Price: 100})
+	a.HandlePrice(websocket.PriceUpdate{Symbol: "BTC-USDT", Stream: "lastPrice", Price: 90})
+	a.HandlePrice(websocket.PriceUpdate{Symbol: "BTC-USDT", Stream: "markPrice", Price: 101})
+
+	c, ok := a.Current("BTC-USDT", time.Hour)
+	if !ok || c.Low != 100 || c.Close != 101 {
+		t.Errorf("candle = %+v, want mark prices only", c)
+	}
+}
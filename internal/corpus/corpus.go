@@ -0,0 +1,206 @@
+package corpus
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ====== TYPES ======
+
+// Entry is one stored announcement.
+type Entry struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code"`
+	CatalogID   int       `json:"catalogId"`
+	Title       string    `json:"title"`
+	ReleaseDate int64     `json:"releaseDate"` // unix milliseconds
+	Body        string    `json:"body,omitempty"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+// Released returns the release date as time.
+func (e Entry) Released() time.Time {
+	return time.UnixMilli(e.ReleaseDate)
+}
+
+// Corpus is an append-only JSON Lines file of announcements, one entry per
+// line. Appending keeps a long backfill resumable: an interrupted run
+// loses at most the line being written.
+type Corpus struct {
+	path    string
+	file    *os.File
+	entries map[int64]Entry
+	mu      sync.Mutex
+}
+
+// ====== OPEN ======
+
+// Open loads the corpus at path, creating it if missing, and prepares it
+// for appends. A later line for the same ID replaces an earlier one.
+func Open(path string) (*Corpus, error) {
+	c := &Corpus{
+		path:    path,
+		entries: make(map[int64]Entry),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("create corpus directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open corpus: %w", err)
+	}
+	c.file = f
+
+	// Terminate a torn last line so appends start on a line of their own.
+	if torn, err := endsTorn(path); err != nil {
+		f.Close()
+		return nil, err
+	} else if torn {
+		if _, err := f.Write([]byte{'\n'}); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("repair corpus: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func endsTorn(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("open corpus: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false, fmt.Errorf("read corpus: %w", err)
+	}
+	return last[0] != '\n', nil
+}
+
+// Load reads the corpus at path without opening it for writing.
+func Load(path string) ([]Entry, error) {
+	c := &Corpus{
+		path:    path,
+		entries: make(map[int64]Entry),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c.Entries(), nil
+}
+
+func (c *Corpus) load() error {
+	f, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open corpus: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024) // bodies can be large
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			// A line torn by an interrupted write is dropped; the next
+			// backfill fetches that article again.
+			log.Printf("Corpus %s line %d skipped: %v", c.path, line, err)
+			continue
+		}
+		c.entries[e.ID] = e
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read corpus: %w", err)
+	}
+	return nil
+}
+
+// ====== ACCESS ======
+
+func (c *Corpus) Has(id int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[id]
+	return ok
+}
+
+func (c *Corpus) Get(id int64) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	return e, ok
+}
+
+func (c *Corpus) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Entries returns every entry, oldest release first.
+func (c *Corpus) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ReleaseDate != out[j].ReleaseDate {
+			return out[i].ReleaseDate < out[j].ReleaseDate
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Add appends e to the file.
+func (c *Corpus) Add(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode corpus entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.Write(data); err != nil {
+		return fmt.Errorf("write corpus entry: %w", err)
+	}
+	c.entries[e.ID] = e
+	return nil
+}
+
+func (c *Corpus) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Close()
+}
@@ -0,0 +1,80 @@
+package news
+
+import (
+	"regexp"
+
+	"bingxGo/internal/binance"
+	"bingxGo/internal/parser"
+)
+
+// ====== KINDS ======
+
+// Kind is the trading-relevant meaning of an announcement.
+type Kind string
+
+const (
+	KindSpotDelist     Kind = "spot_delist"
+	KindFuturesDelist  Kind = "futures_delist"
+	KindMarginDelist   Kind = "margin_delist"
+	KindSpotListing    Kind = "spot_listing"
+	KindFuturesListing Kind = "futures_listing"
+	KindMonitoringTag  Kind = "monitoring_tag"
+	KindOther          Kind = "other"
+)
+
+// IsDelist reports whether the kind removes trading somewhere.
+func (k Kind) IsDelist() bool {
+	return k == KindSpotDelist || k == KindFuturesDelist || k == KindMarginDelist
+}
+
+// ====== RULES ======
+
+type kindRule struct {
+	kind Kind
+	re   *regexp.Regexp
+}
+
+// Checked in order; the first match wins, so the specific rules come first.
+var kindRules = []kindRule{
+	{KindMonitoringTag, regexp.MustCompile(`(?i)monitoring\s+tag`)},
+	{KindFuturesDelist, regexp.MustCompile(`(?i)(USDⓈ-M|COIN-M|futures|perpetual|contracts?).*(delist|clos(e|ing)|cease|settle|remov)|(delist|clos(e|ing)|cease|settle|remov).*(USDⓈ-M|COIN-M|futures|perpetual|contracts?)`)},
+	{KindMarginDelist, regexp.MustCompile(`(?i)margin.*(delist|remov)|(delist|remov).*margin`)},
+	{KindSpotDelist, regexp.MustCompile(`(?i)delist|removal\s+of\s+spot|cease\s+trading`)},
+	{KindFuturesListing, regexp.MustCompile(`(?i)futures\s+will\s+launch|(launch|list).*(USDⓈ-M|perpetual|contracts?)`)},
+	{KindSpotListing, regexp.MustCompile(`(?i)will\s+(list|add)|launchpool|HODLer\s+airdrops|new\s+cryptocurrency\s+listing`)},
+}
+
+// Classify maps an announcement to a Kind from its title; the catalog
+// decides when the title alone is ambiguous.
+func Classify(catalogID int, title string) Kind {
+	for _, r := range kindRules {
+		if r.re.MatchString(title) {
+			return r.kind
+		}
+	}
+
+	switch catalogID {
+	case binance.DelistingCatalogID:
+		return KindSpotDelist
+	case binance.NewListingCatalogID:
+		return KindSpotListing
+	}
+	return KindOther
+}
+
+// ====== MARKETS ======
+
+// AffectedMarkets returns the markets a delisting announcement removes
+// trading from, in spot, margin, futures order; non-delisting kinds yield
+// none. A plain spot delisting also ends margin trading of the token.
+func AffectedMarkets(kind Kind, title string) []parser.Market {
+	switch kind {
+	case KindSpotDelist:
+		return parser.TitleMarkets(title, parser.MarketSpot, parser.MarketMargin)
+	case KindFuturesDelist:
+		return parser.TitleMarkets(title, parser.MarketFutures)
+	case KindMarginDelist:
+		return parser.TitleMarkets(title, parser.MarketMargin)
+	}
+	return nil
+}
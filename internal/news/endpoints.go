@@ -0,0 +1,214 @@
+package news
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"bingxGo/internal/binance"
+)
+
+// ====== ENDPOINTS ======
+
+// Endpoint names.
+const (
+	EndpointList    = "cms-list"
+	EndpointCatalog = "cms-catalog"
+	EndpointDetail  = "cms-detail"
+	EndpointFeed    = "rss"
+)
+
+// Endpoint is one way of listing the newest announcements. The Binance
+// endpoints are served from different caches, so polling several of them
+// and taking whichever reveals an article first cuts detection latency.
+type Endpoint struct {
+	Name string
+	// Fetch lists the newest articles of a catalog.
+	Fetch func(catalogID int) ([]binance.NewsArticle, error)
+	// Global endpoints list every catalog at once; they are fetched once
+	// per round with catalogID 0.
+	Global bool
+	// Observe, if set, is told about every article any endpoint returns.
+	Observe func(a binance.NewsArticle)
+	// External endpoints are not served by Binance and back off on their
+	// own schedule.
+	External bool
+}
+
+// DefaultEndpoints are raced alongside the CMS list API: the catalog API,
+// the article-detail API and, if the client has a feed URL, the RSS feed.
+func DefaultEndpoints(c *binance.BinanceClient, pageSize int) []Endpoint {
+	endpoints := []Endpoint{
+		CatalogEndpoint(c, pageSize),
+		DetailEndpoint(c, defaultDetailProbes),
+	}
+	if c.HasFeed() {
+		endpoints = append(endpoints, FeedEndpoint(c))
+	}
+	return endpoints
+}
+
+// ListEndpoint polls the CMS list API through GetNews.
+func ListEndpoint(src Source, pageSize int) Endpoint {
+	return Endpoint{
+		Name: EndpointList,
+		Fetch: func(catalogID int) ([]binance.NewsArticle, error) {
+			return src.GetNews(catalogID, 1, pageSize)
+		},
+	}
+}
+
+// CatalogEndpoint polls the composite catalog API behind the announcement
+// pages.
+func CatalogEndpoint(c *binance.BinanceClient, pageSize int) Endpoint {
+	return Endpoint{
+		Name: EndpointCatalog,
+		Fetch: func(catalogID int) ([]binance.NewsArticle, error) {
+			return c.GetCatalogNews(catalogID, 1, pageSize)
+		},
+	}
+}
+
+// FeedEndpoint polls the RSS feed of ClientConfig.FeedURL. Feed items
+// have no catalog, so they are classified from the title alone.
+func FeedEndpoint(c *binance.BinanceClient) Endpoint {
+	return Endpoint{
+		Name: EndpointFeed,
+		Fetch: func(int) ([]binance.NewsArticle, error) {
+			return c.GetFeed()
+		},
+		Global:   true,
+		External: true,
+	}
+}
+
+// defaultDetailProbes is how many IDs past the newest known article the
+// detail endpoint tries per round.
+const defaultDetailProbes = 3
+
+// DetailEndpoint probes the article-detail API for the IDs following the
+// newest article seen on any endpoint. Article IDs are shared by every CMS
+// article, so a probe may find an unrelated one; it is classified from
+// its title like feed items. Until another endpoint has reported an
+// article there is nothing to probe from.
+func DetailEndpoint(c *binance.BinanceClient, probes int) Endpoint {
+	if probes <= 0 {
+		probes = defaultDetailProbes
+	}
+	var (
+		newest int64
+		mu     sync.Mutex
+	)
+	return Endpoint{
+		Name:   EndpointDetail,
+		Global: true,
+		Observe: func(a binance.NewsArticle) {
+			mu.Lock()
+			newest = max(newest, a.ID)
+			mu.Unlock()
+		},
+		Fetch: func(int) ([]binance.NewsArticle, error) {
+			mu.Lock()
+			from := newest
+			mu.Unlock()
+			if from == 0 {
+				return nil, nil
+			}
+
+			var found []binance.NewsArticle
+			for id := from + 1; id <= from+int64(probes); id++ {
+				article, err := c.GetArticleByID(id)
+				if errors.Is(err, binance.ErrNoArticle) {
+					continue
+				}
+				if err != nil {
+					return found, err
+				}
+				found = append(found, article.NewsArticle())
+			}
+			return found, nil
+		},
+	}
+}
+
+// ====== RACES ======
+
+// Detection records which endpoint revealed an article first and how far
+// behind the other endpoints were.
+type Detection struct {
+	Key        string // article code, or "id:<id>" without one
+	Title      string
+	Winner     string
+	DetectedAt time.Time
+	Lags       map[string]time.Duration // endpoint -> delay after the winner
+}
+
+// maxDetections bounds the race history kept for Detections.
+const maxDetections = 200
+
+// articleKey matches an article across endpoints; feed items only carry
+// the code.
+func articleKey(a binance.NewsArticle) string {
+	if a.Code != "" {
+		return a.Code
+	}
+	return "id:" + strconv.FormatInt(a.ID, 10)
+}
+
+// startRace records the winning endpoint of a new article. Caller holds
+// detectMu.
+func (p *Poller) startRace(a binance.NewsArticle, endpoint string, at time.Time) {
+	key := articleKey(a)
+	if _, ok := p.races[key]; ok {
+		return
+	}
+	p.races[key] = &Detection{
+		Key:        key,
+		Title:      a.Title,
+		Winner:     endpoint,
+		DetectedAt: at,
+		Lags:       make(map[string]time.Duration),
+	}
+	p.raceOrder = append(p.raceOrder, key)
+	if over := len(p.raceOrder) - maxDetections; over > 0 {
+		for _, k := range p.raceOrder[:over] {
+			delete(p.races, k)
+		}
+		p.raceOrder = append([]string(nil), p.raceOrder[over:]...)
+	}
+}
+
+// trackLag records when a losing endpoint caught up. Caller holds
+// detectMu.
+func (p *Poller) trackLag(a binance.NewsArticle, endpoint string, at time.Time) {
+	d, ok := p.races[articleKey(a)]
+	if !ok || d.Winner == endpoint {
+		return
+	}
+	if _, ok := d.Lags[endpoint]; ok {
+		return
+	}
+	d.Lags[endpoint] = at.Sub(d.DetectedAt)
+	if len(d.Lags) == len(p.endpoints)-1 {
+		log.Printf("Announcement %q: %s won, lags %v", d.Title, d.Winner, d.Lags)
+	}
+}
+
+// Detections returns the recent races, oldest first.
+func (p *Poller) Detections() []Detection {
+	p.detectMu.Lock()
+	defer p.detectMu.Unlock()
+
+	out := make([]Detection, 0, len(p.raceOrder))
+	for _, k := range p.raceOrder {
+		d := *p.races[k]
+		d.Lags = make(map[string]time.Duration, len(p.races[k].Lags))
+		for name, lag := range p.races[k].Lags {
+			d.Lags[name] = lag
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,481 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"bingxGo/internal/binance"
+	"bingxGo/internal/parser"
+)
+
+// ====== TYPES ======
+
+// Source lists announcements; *binance.BinanceClient satisfies it.
+type Source interface {
+	GetNews(catalogID, pageNo, pageSize int) ([]binance.NewsArticle, error)
+}
+
+// MarketLookup reports where a token trades; *binance.MarketIndex
+// satisfies it.
+type MarketLookup interface {
+	Lookup(token string) []parser.Market
+}
+
+// Event is emitted once for every newly detected article.
+type Event struct {
+	Article binance.NewsArticle
+	Kind    Kind
+	Pairs   []string // parser.ExtractPairs on the title, delistings only
+
+	// Markets are the markets the delisting names; Listed maps each pair
+	// to the markets it trades on right now (needs PollerConfig.Markets).
+	Markets []parser.Market
+	Listed  map[string][]parser.Market
+
+	Source     string // endpoint that revealed the article first
+	DetectedAt time.Time
+}
+
+type PollerConfig struct {
+	Catalogs  []int // announcement catalogs to watch
+	PageSize  int
+	Interval  time.Duration // per endpoint, outside hot windows
+	StatePath string        // JSON file with seen article IDs
+
+	// HotWindows are daily UTC ranges when Binance tends to publish; each
+	// endpoint polls every HotInterval inside them. Rate-limit responses
+	// stretch both intervals up to MaxBackoff.
+	HotWindows  []Window
+	HotInterval time.Duration
+	MaxBackoff  time.Duration
+
+	// Endpoints are raced alongside the source given to NewPoller. Their
+	// polls are staggered evenly over the current interval.
+	Endpoints []Endpoint
+
+	// Markets, when set, annotates delisting events with where each pair
+	// currently trades, e.g. to find futures to short on a spot delisting.
+	Markets MarketLookup
+
+	// FireOnFirstRun emits the articles found when a catalog is first
+	// polled. By default every endpoint's view of a new catalog is only
+	// recorded before the endpoints start racing, so a fresh deployment or
+	// a newly added catalog does not trade on old announcements.
+	FireOnFirstRun bool
+}
+
+const (
+	defaultPageSize     = 20
+	defaultPollInterval = 10 * time.Second
+	defaultStatePath    = "./data/seen_announcements.json"
+)
+
+// DefaultCatalogs covers delistings, new listings and general news, which
+// carries monitoring-tag and margin notices.
+var DefaultCatalogs = []int{
+	binance.DelistingCatalogID,
+	binance.NewListingCatalogID,
+	binance.LatestNewsCatalogID,
+}
+
+type subscription struct {
+	kinds map[Kind]bool
+	ch    chan Event
+	done  chan struct{}
+}
+
+// Poller polls Binance announcements and emits new ones to subscribers.
+type Poller struct {
+	endpoints []Endpoint
+	cfg       PollerConfig
+	schedule  *Schedule            // shared by the Binance endpoints
+	external  map[string]*Schedule // own backoff per External endpoint
+	seen      *SeenStore
+	subs      map[int]*subscription
+	nextID    int
+	mu        sync.Mutex
+
+	// detectMu serializes dedup and race tracking across endpoints.
+	races     map[string]*Detection
+	raceOrder []string
+	detectMu  sync.Mutex
+
+	seedMu sync.Mutex
+}
+
+// ====== CONSTRUCTOR ======
+
+// NewPoller polls source through the CMS list API plus any configured
+// endpoints; source may be nil when cfg.Endpoints is set.
+func NewPoller(source Source, cfg PollerConfig) (*Poller, error) {
+	if len(cfg.Catalogs) == 0 {
+		cfg.Catalogs = DefaultCatalogs
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = defaultPageSize
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultPollInterval
+	}
+	if cfg.StatePath == "" {
+		cfg.StatePath = defaultStatePath
+	}
+
+	var endpoints []Endpoint
+	if source != nil {
+		endpoints = append(endpoints, ListEndpoint(source, cfg.PageSize))
+	}
+	endpoints = append(endpoints, cfg.Endpoints...)
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no announcement endpoints")
+	}
+	names := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		if e.Name == "" || e.Fetch == nil {
+			return nil, fmt.Errorf("endpoint %q needs a name and a fetch function", e.Name)
+		}
+		if names[e.Name] {
+			return nil, fmt.Errorf("duplicate endpoint %q", e.Name)
+		}
+		names[e.Name] = true
+	}
+
+	external := make(map[string]*Schedule)
+	for _, e := range endpoints {
+		if e.External {
+			external[e.Name] = newSchedule(cfg)
+		}
+	}
+
+	seen, err := LoadSeen(cfg.StatePath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Poller{
+		endpoints: endpoints,
+		cfg:       cfg,
+		schedule:  newSchedule(cfg),
+		external:  external,
+		seen:      seen,
+		subs:      make(map[int]*subscription),
+		races:     make(map[string]*Detection),
+	}, nil
+}
+
+// ====== SUBSCRIPTIONS ======
+
+// Subscribe returns a channel receiving new articles of the given kinds (or
+// every kind, if none given) and a cancel function. Announcements are rare
+// and must not be lost, so Run waits for slow subscribers rather than
+// dropping events. The channel is closed when Run returns.
+func (p *Poller) Subscribe(buffer int, kinds ...Kind) (<-chan Event, func()) {
+	sub := &subscription{
+		kinds: make(map[Kind]bool, len(kinds)),
+		ch:    make(chan Event, buffer),
+		done:  make(chan struct{}),
+	}
+	for _, k := range kinds {
+		sub.kinds[k] = true
+	}
+
+	p.mu.Lock()
+	id := p.nextID
+	p.nextID++
+	p.subs[id] = sub
+	p.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			p.mu.Lock()
+			delete(p.subs, id)
+			p.mu.Unlock()
+			close(sub.done)
+		})
+	}
+	return sub.ch, cancel
+}
+
+func (p *Poller) publish(ctx context.Context, ev Event) error {
+	p.mu.Lock()
+	targets := make([]*subscription, 0, len(p.subs))
+	for _, sub := range p.subs {
+		if len(sub.kinds) == 0 || sub.kinds[ev.Kind] {
+			targets = append(targets, sub)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, sub := range targets {
+		select {
+		case sub.ch <- ev:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (p *Poller) closeSubs() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, sub := range p.subs {
+		close(sub.ch)
+		delete(p.subs, id)
+	}
+}
+
+// ====== POLLING ======
+
+// Run polls every endpoint until ctx is cancelled. Endpoints run
+// concurrently, each offset by a fraction of the interval, so together
+// they look at Binance more often than any single one.
+func (p *Poller) Run(ctx context.Context) error {
+	p.seedAll()
+	interval := p.schedule.Next(time.Now())
+
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		offset := time.Duration(i) * interval / time.Duration(len(p.endpoints))
+		wg.Add(1)
+		go func(e Endpoint) {
+			defer wg.Done()
+			p.runEndpoint(ctx, e, offset)
+		}(e)
+	}
+	wg.Wait()
+	p.closeSubs()
+	return ctx.Err()
+}
+
+func (p *Poller) runEndpoint(ctx context.Context, e Endpoint, offset time.Duration) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(offset):
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		for _, ev := range p.pollEndpoint(e) {
+			if err := p.publish(ctx, ev); err != nil {
+				return
+			}
+		}
+		timer.Reset(p.scheduleFor(e).Next(time.Now()))
+	}
+}
+
+// PollAll polls every endpoint once, logging failures, and returns the new
+// articles oldest first.
+func (p *Poller) PollAll() []Event {
+	p.seedAll()
+	var events []Event
+	for _, e := range p.endpoints {
+		events = append(events, p.pollEndpoint(e)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Article.ReleaseDate < events[j].Article.ReleaseDate
+	})
+	return events
+}
+
+// pollEndpoint runs one round of an endpoint over the watched catalogs.
+func (p *Poller) pollEndpoint(e Endpoint) []Event {
+	catalogs := p.cfg.Catalogs
+	if e.Global {
+		catalogs = []int{0}
+	}
+
+	// The round counts as rate limited if any catalog was, and feeds the
+	// backoff once.
+	var events []Event
+	var roundErr error
+	for _, catalogID := range catalogs {
+		evs, err := p.Poll(e, catalogID)
+		if err != nil {
+			log.Printf("Announcement poll failed: %v", err)
+			if limited, _ := binance.RateLimited(roundErr); !limited {
+				roundErr = err
+			}
+			continue
+		}
+		events = append(events, evs...)
+	}
+	p.scheduleFor(e).Observe(roundErr)
+	return events
+}
+
+// scheduleFor returns the schedule an endpoint polls and backs off on.
+// Binance endpoints share one, since Binance limits them by IP; a third
+// party's rate limits must not slow them down.
+func (p *Poller) scheduleFor(e Endpoint) *Schedule {
+	if s, ok := p.external[e.Name]; ok {
+		return s
+	}
+	return p.schedule
+}
+
+// Poll fetches the newest articles of a catalog from one endpoint and
+// returns those no endpoint has seen yet, oldest first. They are persisted
+// before they are returned, so a crash never leads to a second trigger.
+// A catalog that has not been seeded yet is seeded instead.
+func (p *Poller) Poll(e Endpoint, catalogID int) ([]Event, error) {
+	if err := p.seed(catalogID); err != nil {
+		return nil, err
+	}
+
+	articles, err := e.Fetch(catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("%s catalog %d: %w", e.Name, catalogID, err)
+	}
+	p.observe(articles)
+
+	p.detectMu.Lock()
+	defer p.detectMu.Unlock()
+
+	now := time.Now()
+	var fresh []binance.NewsArticle
+	batch := make(map[string]bool)
+	for _, a := range articles {
+		if p.seen.Has(a) {
+			p.trackLag(a, e.Name, now)
+			continue
+		}
+		if k := articleKey(a); !batch[k] {
+			batch[k] = true
+			fresh = append(fresh, a)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	if err := p.seen.Record("", fresh...); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(fresh, func(i, j int) bool { return fresh[i].ReleaseDate < fresh[j].ReleaseDate })
+
+	events := make([]Event, len(fresh))
+	for i, a := range fresh {
+		if a.CatalogID == 0 {
+			a.CatalogID = catalogID
+		}
+		kind := Classify(a.CatalogID, a.Title)
+		p.startRace(a, e.Name, now)
+
+		ev := Event{
+			Article:    a,
+			Kind:       kind,
+			Source:     e.Name,
+			DetectedAt: now,
+		}
+		if kind.IsDelist() {
+			ev.Pairs = parser.ExtractPairs(a.Title)
+			ev.Markets = AffectedMarkets(kind, a.Title)
+			if p.cfg.Markets != nil {
+				ev.Listed = make(map[string][]parser.Market, len(ev.Pairs))
+				for _, pair := range ev.Pairs {
+					ev.Listed[pair] = p.cfg.Markets.Lookup(pair)
+				}
+			}
+		}
+		events[i] = ev
+	}
+	return events, nil
+}
+
+// observe tells every endpoint with an Observe hook about articles.
+func (p *Poller) observe(articles []binance.NewsArticle) {
+	for _, e := range p.endpoints {
+		if e.Observe == nil {
+			continue
+		}
+		for _, a := range articles {
+			e.Observe(a)
+		}
+	}
+}
+
+// ====== SEEDING ======
+
+// seedAll seeds every watched catalog, and catalog 0 for the global
+// endpoints, logging failures; Poll retries them.
+func (p *Poller) seedAll() {
+	catalogs := append([]int(nil), p.cfg.Catalogs...)
+	for _, e := range p.endpoints {
+		if e.Global {
+			catalogs = append(catalogs, 0)
+			break
+		}
+	}
+	for _, catalogID := range catalogs {
+		if err := p.seed(catalogID); err != nil {
+			log.Printf("Seeding announcements failed: %v", err)
+		}
+	}
+}
+
+// seed records what every endpoint serving a catalog lists right now, the
+// first time the catalog is polled. Seeding once per catalog rather than
+// per endpoint keeps one endpoint's first poll from swallowing an article
+// that is new to the others. The catalog counts as seeded only when every
+// endpoint answered, so none of them fires on its backlog later.
+func (p *Poller) seed(catalogID int) error {
+	if p.cfg.FireOnFirstRun {
+		return nil
+	}
+	key := seedKey(catalogID)
+	if p.seen.Seeded(key) {
+		return nil
+	}
+
+	p.seedMu.Lock()
+	defer p.seedMu.Unlock()
+	if p.seen.Seeded(key) {
+		return nil
+	}
+
+	var snapshot []binance.NewsArticle
+	var errs []error
+	for _, e := range p.endpoints {
+		if e.Global != (catalogID == 0) {
+			continue
+		}
+		articles, err := e.Fetch(catalogID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s catalog %d: %w", e.Name, catalogID, err))
+			continue
+		}
+		p.observe(articles)
+		snapshot = append(snapshot, articles...)
+	}
+
+	if len(errs) > 0 {
+		key = ""
+	}
+	if err := p.seen.Record(key, snapshot...); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("seeding catalog %d: %w", catalogID, errors.Join(errs...))
+	}
+	log.Printf("Seeded %d existing announcements of catalog %d", len(snapshot), catalogID)
+	return nil
+}
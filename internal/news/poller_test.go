@@ -0,0 +1,104 @@
+package news
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"bingxGo/internal/binance"
+)
+
+// fakeEndpoint lists whatever articles the test gives it.
+type fakeEndpoint struct {
+	name     string
+	articles []binance.NewsArticle
+	mu       sync.Mutex
+}
+
+func (f *fakeEndpoint) set(ids ...int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.articles = f.articles[:0]
+	for _, id := range ids {
+		f.articles = append(f.articles, binance.NewsArticle{
+			ID:          id,
+			Title:       "Binance Will Delist ABC on 2025-01-01",
+			ReleaseDate: id,
+		})
+	}
+}
+
+func (f *fakeEndpoint) endpoint() Endpoint {
+	return Endpoint{
+		Name: f.name,
+		Fetch: func(int) ([]binance.NewsArticle, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return append([]binance.NewsArticle(nil), f.articles...), nil
+		},
+	}
+}
+
+func newTestPoller(t *testing.T, state string, endpoints ...*fakeEndpoint) *Poller {
+	t.Helper()
+	cfg := PollerConfig{
+		Catalogs:  []int{binance.DelistingCatalogID},
+		StatePath: state,
+	}
+	for _, f := range endpoints {
+		cfg.Endpoints = append(cfg.Endpoints, f.endpoint())
+	}
+	p, err := NewPoller(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func eventIDs(evs []Event) map[int64]string {
+	out := make(map[int64]string, len(evs))
+	for _, ev := range evs {
+		out[ev.Article.ID] = ev.Source
+	}
+	return out
+}
+
+func TestSeedingAcrossEndpointsAndRestart(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "seen.json")
+	a := &fakeEndpoint{name: "a"}
+	b := &fakeEndpoint{name: "b"}
+
+	// First deployment: both endpoints' backlog is recorded, not fired.
+	a.set(1, 2)
+	b.set(2, 3)
+	p := newTestPoller(t, state, a, b)
+	if evs := p.PollAll(); len(evs) != 0 {
+		t.Fatalf("backlog fired: %v", eventIDs(evs))
+	}
+
+	// A new article reaches b first and fires once.
+	b.set(2, 3, 4)
+	if got := eventIDs(p.PollAll()); len(got) != 1 || got[4] != "b" {
+		t.Fatalf("events = %v, want 4 from b", got)
+	}
+	a.set(1, 2, 4)
+	if evs := p.PollAll(); len(evs) != 0 {
+		t.Fatalf("duplicate events: %v", eventIDs(evs))
+	}
+
+	// Restart with an extra endpoint while article 5 was published. The
+	// new endpoint's first poll must not swallow it.
+	c := &fakeEndpoint{name: "c"}
+	c.set(1, 2, 3, 4, 5)
+	a.set(1, 2, 4, 5)
+	p = newTestPoller(t, state, c, a, b)
+	if got := eventIDs(p.PollAll()); len(got) != 1 || got[5] != "c" {
+		t.Fatalf("after restart events = %v, want 5 from c", got)
+	}
+
+	// And nothing fires again after another restart.
+	p = newTestPoller(t, state, a, b, c)
+	if evs := p.PollAll(); len(evs) != 0 {
+		t.Fatalf("re-fired after restart: %v", eventIDs(evs))
+	}
+}
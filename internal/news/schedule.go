@@ -0,0 +1,171 @@
+package news
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"bingxGo/internal/binance"
+)
+
+// ====== WINDOWS ======
+
+// Window is a daily UTC time range. Start after End wraps past midnight.
+type Window struct {
+	Start time.Duration // offset from 00:00 UTC
+	End   time.Duration
+}
+
+// ParseWindows parses "HH:MM-HH:MM" ranges separated by commas, e.g.
+// "06:00-10:00,22:30-01:00".
+func ParseWindows(s string) ([]Window, error) {
+	var windows []Window
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("window %q: want HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %w", part, err)
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %w", part, err)
+		}
+		windows = append(windows, Window{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls inside the window.
+func (w Window) Contains(t time.Time) bool {
+	t = t.UTC()
+	of := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.Start <= w.End {
+		return of >= w.Start && of < w.End
+	}
+	return of >= w.Start || of < w.End
+}
+
+// ====== SCHEDULE ======
+
+const defaultMaxBackoff = 5 * time.Minute
+
+// Schedule decides how long to wait between polls: HotInterval inside the
+// hot windows, Interval outside, stretched while Binance answers with
+// 429/418/403. The Binance endpoints share one, since Binance limits by
+// IP; each external endpoint has its own.
+type Schedule struct {
+	windows    []Window
+	hot        time.Duration
+	cold       time.Duration
+	maxBackoff time.Duration
+
+	factor     int       // backoff multiplier, 1 when healthy
+	changedAt  time.Time // last factor change; one change per round
+	pauseUntil time.Time // honour Retry-After
+	mu         sync.Mutex
+}
+
+func newSchedule(cfg PollerConfig) *Schedule {
+	hot := cfg.HotInterval
+	if hot <= 0 {
+		hot = cfg.Interval
+	}
+	maxBackoff := cfg.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	return &Schedule{
+		windows:    cfg.HotWindows,
+		hot:        hot,
+		cold:       cfg.Interval,
+		maxBackoff: maxBackoff,
+		factor:     1,
+	}
+}
+
+// Hot reports whether t is inside a hot window.
+func (s *Schedule) Hot(t time.Time) bool {
+	for _, w := range s.windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the wait before the next poll at now.
+func (s *Schedule) Next(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := s.round(now)
+	if now.Before(s.pauseUntil) {
+		if pause := s.pauseUntil.Sub(now); pause > wait {
+			wait = pause
+		}
+	}
+	return wait
+}
+
+// round is the current poll interval with backoff; s.mu must be held.
+func (s *Schedule) round(now time.Time) time.Duration {
+	base := s.cold
+	if s.Hot(now) {
+		base = s.hot
+	}
+	wait := base * time.Duration(s.factor)
+	if wait > s.maxBackoff && base < s.maxBackoff {
+		wait = s.maxBackoff
+	}
+	return wait
+}
+
+// Observe adjusts the backoff from the result of an endpoint's poll
+// round: rate-limit errors double it, successes halve it back towards
+// normal. Endpoints may share a schedule, so the factor moves at most
+// once per round however many of them report.
+func (s *Schedule) Observe(err error) {
+	limited, retry := binance.RateLimited(err)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limited && retry > 0 {
+		s.pauseUntil = now.Add(retry)
+	}
+	if now.Sub(s.changedAt) < s.round(now) {
+		return
+	}
+
+	switch {
+	case limited && s.cold*time.Duration(s.factor) < s.maxBackoff:
+		s.factor *= 2
+		s.changedAt = now
+		log.Printf("Announcement source rate limited (%v), slowing polls %dx", err, s.factor)
+	case err == nil && s.factor > 1:
+		s.factor /= 2
+		s.changedAt = now
+		if s.factor == 1 {
+			log.Println("Announcement polling back to normal rate")
+		}
+	}
+}
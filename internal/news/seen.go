@@ -0,0 +1,177 @@
+package news
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"bingxGo/internal/binance"
+)
+
+// ====== SEEN STORE ======
+
+// SeenStore persists announcements already handled, so restarts never
+// re-fire on old articles. Articles are matched by numeric ID or by code,
+// since feed items carry only the latter. Both lists keep insertion order
+// and are trimmed to the newest limit entries.
+type SeenStore struct {
+	path   string
+	limit  int
+	ids    []int64
+	codes  []string
+	byID   map[int64]bool
+	byCode map[string]bool
+	// seeded holds the catalog keys whose backlog was recorded on first
+	// poll.
+	seeded map[string]bool
+	mu     sync.Mutex
+}
+
+type seenFile struct {
+	IDs    []int64  `json:"ids"`
+	Codes  []string `json:"codes,omitempty"`
+	Seeded []string `json:"seeded,omitempty"`
+}
+
+// LoadSeen reads the store at path; a missing file yields an empty store.
+func LoadSeen(path string, limit int) (*SeenStore, error) {
+	if limit <= 0 {
+		limit = 10000
+	}
+	s := &SeenStore{
+		path:   path,
+		limit:  limit,
+		byID:   make(map[int64]bool),
+		byCode: make(map[string]bool),
+		seeded: make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read seen IDs: %w", err)
+	}
+
+	var f seenFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode seen IDs %s: %w", path, err)
+	}
+	for _, id := range f.IDs {
+		s.addID(id)
+	}
+	for _, code := range f.Codes {
+		s.addCode(code)
+	}
+	for _, key := range f.Seeded {
+		s.seeded[key] = true
+	}
+	return s, nil
+}
+
+// seedKey names a catalog in Seeded; catalog 0 is the global endpoints.
+func seedKey(catalogID int) string {
+	return "catalog/" + strconv.Itoa(catalogID)
+}
+
+// Has reports whether the article was recorded under its ID or code.
+func (s *SeenStore) Has(a binance.NewsArticle) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return (a.ID != 0 && s.byID[a.ID]) || (a.Code != "" && s.byCode[a.Code])
+}
+
+func (s *SeenStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.ids) + len(s.codes)
+}
+
+// Seeded reports whether key has been polled before.
+func (s *SeenStore) Seeded(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seeded[key]
+}
+
+// Record stores articles, marks key as seeded when non-empty and writes
+// the store to disk before returning.
+func (s *SeenStore) Record(key string, articles ...binance.NewsArticle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key != "" {
+		s.seeded[key] = true
+	}
+	for _, a := range articles {
+		if a.ID != 0 {
+			s.addID(a.ID)
+		}
+		if a.Code != "" {
+			s.addCode(a.Code)
+		}
+	}
+
+	if over := len(s.ids) - s.limit; over > 0 {
+		for _, id := range s.ids[:over] {
+			delete(s.byID, id)
+		}
+		s.ids = append([]int64(nil), s.ids[over:]...)
+	}
+	if over := len(s.codes) - s.limit; over > 0 {
+		for _, code := range s.codes[:over] {
+			delete(s.byCode, code)
+		}
+		s.codes = append([]string(nil), s.codes[over:]...)
+	}
+	return s.save()
+}
+
+func (s *SeenStore) addID(id int64) {
+	if !s.byID[id] {
+		s.byID[id] = true
+		s.ids = append(s.ids, id)
+	}
+}
+
+func (s *SeenStore) addCode(code string) {
+	if !s.byCode[code] {
+		s.byCode[code] = true
+		s.codes = append(s.codes, code)
+	}
+}
+
+// save writes atomically through a temp file and rename.
+func (s *SeenStore) save() error {
+	f := seenFile{IDs: s.ids, Codes: s.codes}
+	for key := range s.seeded {
+		f.Seeded = append(f.Seeded, key)
+	}
+	sort.Strings(f.Seeded)
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("encode seen IDs: %w", err)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create state directory: %w", err)
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write seen IDs: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace seen IDs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package news
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bingxGo/internal/binance"
+)
+
+func loadSeen(t *testing.T, path string, limit int) *SeenStore {
+	t.Helper()
+	s, err := LoadSeen(path, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSeenStoreSaveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "seen.json")
+	s := loadSeen(t, path, 0)
+	if s.Len() != 0 {
+		t.Fatalf("missing file loaded %d entries", s.Len())
+	}
+
+	byID := binance.NewsArticle{ID: 1, Code: "abc"}
+	byCode := binance.NewsArticle{Code: "feed-only"}
+	if err := s.Record(seedKey(161), byID, byCode); err != nil {
+		t.Fatal(err)
+	}
+	// Recording the same article again must not duplicate it.
+	if err := s.Record("", byID); err != nil {
+		t.Fatal(err)
+	}
+
+	s = loadSeen(t, path, 0)
+	if s.Len() != 3 {
+		t.Errorf("reloaded %d entries, want 3", s.Len())
+	}
+	for _, a := range []binance.NewsArticle{
+		{ID: 1},
+		{Code: "abc"},
+		{Code: "feed-only"},
+		{ID: 99, Code: "abc"}, // a feed copy of a known article
+	} {
+		if !s.Has(a) {
+			t.Errorf("Has(%+v) = false after reload", a)
+		}
+	}
+	if s.Has(binance.NewsArticle{ID: 2, Code: "other"}) || s.Has(binance.NewsArticle{}) {
+		t.Error("Has matched an unseen article")
+	}
+	if !s.Seeded(seedKey(161)) || s.Seeded(seedKey(48)) {
+		t.Error("seeded keys not restored")
+	}
+}
+
+func TestSeenStoreTrimsToLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+	s := loadSeen(t, path, 2)
+	for id := int64(1); id <= 3; id++ {
+		if err := s.Record("", binance.NewsArticle{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s = loadSeen(t, path, 2)
+	if s.Has(binance.NewsArticle{ID: 1}) || !s.Has(binance.NewsArticle{ID: 2}) || !s.Has(binance.NewsArticle{ID: 3}) {
+		t.Errorf("store kept the wrong IDs: %v", s.ids)
+	}
+}
+
+func TestSeenStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+	if err := os.WriteFile(path, []byte(`{"ids":[1,`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSeen(path, 0); err == nil {
+		t.Fatal("corrupt store loaded without error")
+	}
+}
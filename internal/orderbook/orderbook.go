@@ -0,0 +1,451 @@
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"bingxGo/internal/bingx"
+	"bingxGo/internal/websocket"
+)
+
+// ====== TYPES ======
+
+// Snapshot is a full REST depth snapshot. LastUpdateID is the sequence ID
+// it reflects, 0 when the source does not report one.
+type Snapshot struct {
+	LastUpdateID int64
+	Bids         []websocket.Level
+	Asks         []websocket.Level
+}
+
+// SnapshotFunc fetches a full REST depth snapshot for a symbol.
+type SnapshotFunc func(symbol string) (*Snapshot, error)
+
+// DepthSummary is the cumulative liquidity within a price band around mid.
+type DepthSummary struct {
+	BidQty      float64
+	AskQty      float64
+	BidNotional float64
+	AskNotional float64
+}
+
+// Book is an in-memory L2 order book for one symbol.
+type Book struct {
+	symbol       string
+	bids         map[float64]float64
+	asks         map[float64]float64
+	lastUpdateID int64
+	synced       bool
+	resyncing    bool
+	fetching     bool                    // a snapshot is in flight
+	pending      []websocket.DepthUpdate // increments seen while fetching
+	snapshot     SnapshotFunc
+	ctx          context.Context // ends background resyncs on Close
+	cancel       context.CancelFunc
+	mu           sync.RWMutex
+	syncMu       sync.Mutex // one Sync at a time
+}
+
+// Manager keeps one book per symbol and consumes depth events.
+type Manager struct {
+	books    map[string]*Book
+	snapshot SnapshotFunc
+	mu       sync.RWMutex
+}
+
+// ====== CONSTRUCTORS ======
+
+func NewManager(snapshot SnapshotFunc) *Manager {
+	return &Manager{
+		books:    make(map[string]*Book),
+		snapshot: snapshot,
+	}
+}
+
+func NewBook(symbol string, snapshot SnapshotFunc) *Book {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Book{
+		symbol:   symbol,
+		bids:     make(map[float64]float64),
+		asks:     make(map[float64]float64),
+		snapshot: snapshot,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// BingXSnapshot returns a SnapshotFunc backed by bingx.FetchDepth.
+func BingXSnapshot(limit int) SnapshotFunc {
+	return func(symbol string) (*Snapshot, error) {
+		res, err := bingx.FetchDepth(symbol, limit)
+		if err != nil {
+			return nil, err
+		}
+		bids, err := parseLevels(res.Data.Bids)
+		if err != nil {
+			return nil, err
+		}
+		asks, err := parseLevels(res.Data.Asks)
+		if err != nil {
+			return nil, err
+		}
+		return &Snapshot{LastUpdateID: res.Data.LastUpdateID, Bids: bids, Asks: asks}, nil
+	}
+}
+
+// ====== MANAGER ======
+
+// Book returns the book for symbol, creating and syncing it on first use.
+func (m *Manager) Book(symbol string) (*Book, error) {
+	b, created := m.get(symbol)
+	if !created {
+		return b, nil
+	}
+	return b, b.Sync()
+}
+
+// HandleDepth applies a depth event; use it as websocket.Handlers.OnDepth.
+// Unknown symbols get a book that syncs in the background, so the read
+// loop never waits on REST.
+func (m *Manager) HandleDepth(u websocket.DepthUpdate) {
+	b, created := m.get(u.Symbol)
+	if created {
+		b.mu.Lock()
+		b.resync()
+		b.mu.Unlock()
+	}
+	b.Apply(u)
+}
+
+// Close stops the background resyncs of every book.
+func (m *Manager) Close() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, b := range m.books {
+		b.Close()
+	}
+}
+
+// get returns the book for symbol and whether it was just created.
+func (m *Manager) get(symbol string) (*Book, bool) {
+	m.mu.RLock()
+	b, ok := m.books[symbol]
+	m.mu.RUnlock()
+	if ok {
+		return b, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok = m.books[symbol]; ok {
+		return b, false
+	}
+	b = NewBook(symbol, m.snapshot)
+	m.books[symbol] = b
+	return b, true
+}
+
+// ====== SYNC ======
+
+const maxPending = 10000
+
+// Resync retry delays, variables so tests can shorten them.
+var (
+	resyncBackoff    = time.Second
+	resyncMaxBackoff = time.Minute
+)
+
+// Sync replaces the book with a REST snapshot. Increments that arrive while
+// it is fetched are buffered and replayed on top: those at or below the
+// snapshot's update ID are dropped, and the first one kept must follow it.
+// Concurrent calls run one after another.
+func (b *Book) Sync() error {
+	if b.snapshot == nil {
+		return fmt.Errorf("no snapshot source for %s", b.symbol)
+	}
+
+	b.syncMu.Lock()
+	defer b.syncMu.Unlock()
+
+	b.mu.Lock()
+	b.fetching = true
+	b.synced = false
+	b.mu.Unlock()
+
+	snap, err := b.snapshot(b.symbol)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fetching = false
+	if err != nil {
+		// Buffered increments wait for the next attempt.
+		return fmt.Errorf("snapshot %s: %w", b.symbol, err)
+	}
+	pending := b.pending
+	b.pending = nil
+
+	b.replace(snap.Bids, snap.Asks)
+	b.lastUpdateID = snap.LastUpdateID
+	for i, u := range pending {
+		if snap.LastUpdateID != 0 && u.LastUpdateID <= snap.LastUpdateID {
+			continue
+		}
+		if b.lastUpdateID != 0 && u.LastUpdateID != 0 && u.LastUpdateID != b.lastUpdateID+1 {
+			// Keep the rest for a newer snapshot.
+			b.pending = append([]websocket.DepthUpdate(nil), pending[i:]...)
+			b.synced = false
+			return fmt.Errorf("snapshot %s at %d does not meet stream at %d", b.symbol, b.lastUpdateID, u.LastUpdateID)
+		}
+		b.merge(u)
+	}
+	b.synced = true
+	return nil
+}
+
+// resync fetches a new snapshot in the background after a sequence gap,
+// retrying with exponential backoff until one lines up with the stream or
+// the book is closed. Callers must hold b.mu.
+func (b *Book) resync() {
+	b.synced = false
+	if b.resyncing || b.ctx.Err() != nil {
+		return
+	}
+	b.resyncing = true
+
+	go func() {
+		backoff := resyncBackoff
+		for b.ctx.Err() == nil {
+			err := b.Sync()
+			if err == nil {
+				break
+			}
+			log.Printf("Order book %s resync failed, retrying in %s: %v", b.symbol, backoff, err)
+			select {
+			case <-b.ctx.Done():
+			case <-time.After(backoff):
+			}
+			if backoff < resyncMaxBackoff {
+				backoff *= 2
+			}
+		}
+		b.mu.Lock()
+		b.resyncing = false
+		b.mu.Unlock()
+	}()
+}
+
+// Close stops background resyncs; a snapshot already being fetched still
+// completes.
+func (b *Book) Close() {
+	b.cancel()
+}
+
+// ====== UPDATES ======
+
+// Apply merges a depth event. Full snapshots ("all" action, or depthN
+// streams without sequence IDs) replace the book; increments must be
+// contiguous or the book resyncs.
+func (b *Book) Apply(u websocket.DepthUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if u.Action == "all" || (u.Action == "" && u.LastUpdateID == 0) {
+		b.replace(u.Bids, u.Asks)
+		b.lastUpdateID = u.LastUpdateID
+		b.synced = true
+		return
+	}
+
+	if !b.synced {
+		// A book that lost sync (failed Sync, gap) keeps retrying until it
+		// is back; increments meanwhile are buffered for the replay.
+		if !b.resyncing && !b.fetching && b.snapshot != nil {
+			b.resync()
+		}
+		if b.fetching || b.resyncing {
+			if len(b.pending) == maxPending {
+				b.pending = b.pending[1:]
+			}
+			b.pending = append(b.pending, u)
+		}
+		return
+	}
+
+	if b.lastUpdateID != 0 && u.LastUpdateID != 0 {
+		if u.LastUpdateID <= b.lastUpdateID {
+			return
+		}
+		if u.LastUpdateID != b.lastUpdateID+1 {
+			log.Printf("Order book %s gap: have %d, got %d, resyncing", b.symbol, b.lastUpdateID, u.LastUpdateID)
+			b.resync()
+			return
+		}
+	}
+
+	b.merge(u)
+}
+
+func (b *Book) merge(u websocket.DepthUpdate) {
+	applyLevels(b.bids, u.Bids)
+	applyLevels(b.asks, u.Asks)
+	b.lastUpdateID = u.LastUpdateID
+}
+
+func (b *Book) replace(bids, asks []websocket.Level) {
+	b.bids = make(map[float64]float64, len(bids))
+	b.asks = make(map[float64]float64, len(asks))
+	applyLevels(b.bids, bids)
+	applyLevels(b.asks, asks)
+}
+
+// applyLevels sets quantities; a zero quantity removes the level.
+func applyLevels(side map[float64]float64, levels []websocket.Level) {
+	for _, l := range levels {
+		if l.Quantity == 0 {
+			delete(side, l.Price)
+			continue
+		}
+		side[l.Price] = l.Quantity
+	}
+}
+
+// ====== QUERIES ======
+
+// Synced reports whether the book reflects the exchange state.
+func (b *Book) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+func (b *Book) BestBid() (websocket.Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return best(b.bids, true)
+}
+
+func (b *Book) BestAsk() (websocket.Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return best(b.asks, false)
+}
+
+// Mid returns the midpoint between best bid and best ask.
+func (b *Book) Mid() (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.mid()
+}
+
+// Spread returns the absolute spread and the spread relative to mid in percent.
+func (b *Book) Spread() (abs, pct float64, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bid, okBid := best(b.bids, true)
+	ask, okAsk := best(b.asks, false)
+	if !okBid || !okAsk {
+		return 0, 0, false
+	}
+	abs = ask.Price - bid.Price
+	mid := (ask.Price + bid.Price) / 2
+	return abs, abs / mid * 100, true
+}
+
+// DepthWithin sums bid and ask liquidity priced within pct percent of mid.
+func (b *Book) DepthWithin(pct float64) (DepthSummary, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	mid, ok := b.mid()
+	if !ok {
+		return DepthSummary{}, false
+	}
+	low, high := mid*(1-pct/100), mid*(1+pct/100)
+
+	var s DepthSummary
+	for price, qty := range b.bids {
+		if price >= low {
+			s.BidQty += qty
+			s.BidNotional += price * qty
+		}
+	}
+	for price, qty := range b.asks {
+		if price <= high {
+			s.AskQty += qty
+			s.AskNotional += price * qty
+		}
+	}
+	return s, true
+}
+
+// Levels returns up to n levels per side, best first.
+func (b *Book) Levels(n int) (bids, asks []websocket.Level) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sorted(b.bids, true, n), sorted(b.asks, false, n)
+}
+
+func (b *Book) mid() (float64, bool) {
+	bid, okBid := best(b.bids, true)
+	ask, okAsk := best(b.asks, false)
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return (bid.Price + ask.Price) / 2, true
+}
+
+// ====== UTILITIES ======
+
+func best(side map[float64]float64, highest bool) (websocket.Level, bool) {
+	var lvl websocket.Level
+	found := false
+	for price, qty := range side {
+		if !found || (highest && price > lvl.Price) || (!highest && price < lvl.Price) {
+			lvl = websocket.Level{Price: price, Quantity: qty}
+			found = true
+		}
+	}
+	return lvl, found
+}
+
+func sorted(side map[float64]float64, desc bool, n int) []websocket.Level {
+	levels := make([]websocket.Level, 0, len(side))
+	for price, qty := range side {
+		levels = append(levels, websocket.Level{Price: price, Quantity: qty})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if desc {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+	if n > 0 && len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
+
+func parseLevels(raw [][]string) ([]websocket.Level, error) {
+	levels := make([]websocket.Level, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(l[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("level price %s: %w", l[0], err)
+		}
+		qty, err := strconv.ParseFloat(l[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("level quantity %s: %w", l[1], err)
+		}
+		levels = append(levels, websocket.Level{Price: price, Quantity: qty})
+	}
+	return levels, nil
+}
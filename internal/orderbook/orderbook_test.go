@@ -0,0 +1,246 @@
+package orderbook
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bingxGo/internal/websocket"
+)
+
+func delta(id int64, price, qty float64) websocket.DepthUpdate {
+	return websocket.DepthUpdate{
+		Symbol:       "BTC-USDT",
+		Action:       "update",
+		LastUpdateID: id,
+		Bids:         []websocket.Level{{Price: price, Quantity: qty}},
+	}
+}
+
+// fakeSnapshots serves snapshots in order, blocking each one on release
+// when it is set, and fails while failures remain.
+type fakeSnapshots struct {
+	mu       sync.Mutex
+	snaps    []*Snapshot
+	failures int
+	calls    int
+	release  chan struct{}
+	started  chan struct{}
+}
+
+func (f *fakeSnapshots) fetch(string) (*Snapshot, error) {
+	f.mu.Lock()
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		f.mu.Unlock()
+		return nil, errors.New("rate limited")
+	}
+	snap := f.snaps[0]
+	if len(f.snaps) > 1 {
+		f.snaps = f.snaps[1:]
+	}
+	release, started := f.release, f.started
+	f.mu.Unlock()
+
+	if started != nil {
+		started <- struct{}{}
+	}
+	if release != nil {
+		<-release
+	}
+	return snap, nil
+}
+
+func waitSynced(t *testing.T, b *Book) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !b.Synced() {
+		if time.Now().After(deadline) {
+			t.Fatal("book did not resync")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGapResyncReplaysBufferedDeltas(t *testing.T) {
+	f := &fakeSnapshots{snaps: []*Snapshot{
+		{LastUpdateID: 10, Bids: []websocket.Level{{Price: 100, Quantity: 1}}},
+		{LastUpdateID: 20, Bids: []websocket.Level{{Price: 100, Quantity: 5}}},
+	}}
+	b := NewBook("BTC-USDT", f.fetch)
+	if err := b.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Apply(delta(11, 99, 2))
+	if bids, _ := b.Levels(0); len(bids) != 2 {
+		t.Fatalf("bids after delta = %v", bids)
+	}
+
+	// The gap starts a resync; hold its snapshot while the stream moves on.
+	f.mu.Lock()
+	f.release, f.started = make(chan struct{}), make(chan struct{})
+	f.mu.Unlock()
+	b.Apply(delta(15, 98, 1))
+	<-f.started
+	if b.Synced() {
+		t.Fatal("book still synced after a gap")
+	}
+
+	b.Apply(delta(19, 97, 1)) // covered by the snapshot
+	b.Apply(delta(20, 96, 1)) // covered by the snapshot
+	b.Apply(delta(21, 95, 3))
+	b.Apply(delta(22, 100, 0))
+	close(f.release)
+	waitSynced(t, b)
+
+	bids, _ := b.Levels(0)
+	want := []websocket.Level{{Price: 95, Quantity: 3}}
+	if len(bids) != 1 || bids[0] != want[0] {
+		t.Fatalf("bids after resync = %v, want %v", bids, want)
+	}
+
+	b.Apply(delta(23, 94, 1))
+	if bids, _ := b.Levels(0); len(bids) != 2 {
+		t.Errorf("live delta after resync not applied: %v", bids)
+	}
+}
+
+func TestResyncRetriesFailedSnapshots(t *testing.T) {
+	resyncBackoff = time.Millisecond
+	t.Cleanup(func() { resyncBackoff = time.Second })
+
+	f := &fakeSnapshots{
+		snaps:    []*Snapshot{{LastUpdateID: 5, Asks: []websocket.Level{{Price: 101, Quantity: 1}}}},
+		failures: 2,
+	}
+	m := NewManager(f.fetch)
+	m.HandleDepth(delta(6, 100, 1))
+
+	b, _ := m.Book("BTC-USDT")
+	waitSynced(t, b)
+	f.mu.Lock()
+	calls := f.calls
+	f.mu.Unlock()
+	if calls != 3 {
+		t.Errorf("snapshot calls = %d, want 3", calls)
+	}
+	if mid, ok := b.Mid(); !ok || mid != 100.5 {
+		t.Errorf("mid = %v, %v; want the buffered bid replayed", mid, ok)
+	}
+}
+
+func TestSnapshotBehindStreamRetries(t *testing.T) {
+	resyncBackoff = time.Millisecond
+	t.Cleanup(func() { resyncBackoff = time.Second })
+
+	f := &fakeSnapshots{snaps: []*Snapshot{{LastUpdateID: 1}}}
+	b := NewBook("BTC-USDT", f.fetch)
+	if err := b.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first resync snapshot is stale: it cannot bridge to delta 6.
+	release := make(chan struct{})
+	f.mu.Lock()
+	f.snaps = []*Snapshot{{LastUpdateID: 1}, {LastUpdateID: 6, Bids: []websocket.Level{{Price: 100, Quantity: 1}}}}
+	f.release, f.started = release, make(chan struct{})
+	started := f.started
+	f.mu.Unlock()
+
+	b.Apply(delta(5, 100, 1))
+	<-started
+	b.Apply(delta(6, 100, 1))
+	b.Apply(delta(7, 99, 2))
+
+	f.mu.Lock()
+	f.release, f.started = nil, nil
+	f.mu.Unlock()
+	close(release)
+
+	waitSynced(t, b)
+	bids, _ := b.Levels(0)
+	if len(bids) != 2 || bids[1] != (websocket.Level{Price: 99, Quantity: 2}) {
+		t.Errorf("bids = %v", bids)
+	}
+}
+
+func TestConcurrentSyncsReplayDeltasOnce(t *testing.T) {
+	f := &fakeSnapshots{snaps: []*Snapshot{{LastUpdateID: 1}}}
+	b := NewBook("BTC-USDT", f.fetch)
+	if err := b.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first Sync gets a stale snapshot while deltas 5-7 arrive; a
+	// second Sync queued behind it must replay each of them once.
+	release := make(chan struct{})
+	f.mu.Lock()
+	f.snaps = []*Snapshot{{LastUpdateID: 1}, {LastUpdateID: 4, Bids: []websocket.Level{{Price: 100, Quantity: 1}}}}
+	f.release, f.started = release, make(chan struct{})
+	started := f.started
+	f.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = b.Sync()
+	}()
+	<-started
+
+	b.Apply(delta(5, 99, 1))
+	b.Apply(delta(6, 98, 1))
+	b.Apply(delta(7, 97, 1))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[1] = b.Sync()
+	}()
+	close(release)
+	<-started
+	wg.Wait()
+
+	if errs[0] == nil {
+		t.Error("stale snapshot synced")
+	}
+	if errs[1] != nil {
+		t.Fatalf("second Sync: %v", errs[1])
+	}
+	if bids, _ := b.Levels(0); len(bids) != 4 {
+		t.Errorf("bids = %v, want the snapshot level and three deltas", bids)
+	}
+	b.Apply(delta(8, 96, 1))
+	if bids, _ := b.Levels(0); len(bids) != 5 || !b.Synced() {
+		t.Errorf("live delta after sync not applied: %v", bids)
+	}
+}
+
+func TestCloseStopsResync(t *testing.T) {
+	resyncBackoff = time.Millisecond
+	t.Cleanup(func() { resyncBackoff = time.Second })
+
+	f := &fakeSnapshots{failures: 1 << 30}
+	m := NewManager(f.fetch)
+	m.HandleDepth(delta(1, 100, 1))
+
+	calls := func() int {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.calls
+	}
+	for calls() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	m.Close()
+	time.Sleep(20 * time.Millisecond)
+	n := calls()
+	time.Sleep(50 * time.Millisecond)
+	if got := calls(); got != n {
+		t.Errorf("%d snapshot calls after Close", got-n)
+	}
+}
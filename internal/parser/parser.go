@@ -1,16 +1,167 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// Precompiled regex patterns for performance
-var delistPatterns = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)Binance\s+Will\s+Delist\s+(.+?)\s+on\s+\d{4}-\d{2}-\d{2}`),
-	regexp.MustCompile(`(?i)Binance\s+Announced\s+the\s+First\s+Batch\s+of\s+Vote\s+to\s+Delist\s+Results\s+and\s+Will\s+Delist\s+(.+?)\s+on\s+\d{4}-\d{2}-\d{2}`),
+// Market is a Binance market an announcement applies to.
+type Market string
+
+const (
+	MarketSpot    Market = "spot"
+	MarketFutures Market = "futures"
+	MarketMargin  Market = "margin"
+)
+
+// Announcement is a parsed delisting title.
+type Announcement struct {
+	Title      string
+	Pairs      []string  // tokens or pairs as written, e.g. "BTC" or "BTCUSDT"
+	DelistAt   time.Time // UTC; zero when the title has no date
+	HasTime    bool      // the title gave a time of day, not only a date
+	Markets    []Market
+	Batch      int     // "Vote to Delist" batch number, 0 otherwise
+	Pattern    string  // name of the matched pattern
+	Confidence float64 // 0..1, how much the match can be trusted
+}
+
+// ErrNoMatch is returned when no delisting pattern matches a title.
+var ErrNoMatch = errors.New("no known delisting pattern matched")
+
+// Title fragments shared by the built-in rules.
+const (
+	onDate  = `(?:\s+on\s+|\s*[-–]\s*|\s+)\(?(?P<date>\d{4}-\d{2}-\d{2})(?:\s+(?:at\s+)?(?P<time>\d{1,2}:\d{2})\s*\(?UTC\)?)?\)?`
+	optDate = `(?:` + onDate + `)?`
+	ordinal = `(?P<batch>First|Second|Third|Fourth|Fifth|Sixth|Seventh|Eighth|Ninth|Tenth|\d+(?:st|nd|rd|th)?)`
+	// A list of upper-case tickers or "Name (TICKER)" items, for titles
+	// with nothing after the pairs to stop at. Free prose does not match.
+	tickerItem = `(?:(?-i:[A-Z0-9]{2,15})|[A-Za-z0-9.]+(?:\s+[A-Za-z0-9.]+){0,2}\s*\((?-i:[A-Z0-9]{2,15})\))`
+	tickers    = `(?P<pairs>` + tickerItem + `(?:\s*(?:,|;|&|\s+and\s+)\s*` + tickerItem + `)*)`
+	updated    = `(?:\s*\(Updated\))?`
+)
+
+// defaultRules are used until a rules file is loaded, most specific
+// first. Each captures "pairs" and "date", optionally "time", "batch" and
+// "scope" (Futures or Margin, overriding the rule's market).
+var defaultRules = []Rule{
+	{
+		Name:   "vote-to-delist",
+		Market: MarketSpot,
+		Regex:  `(?i)Binance\s+Announced\s+the\s+` + ordinal + `\s+Batch\s+of\s+Vote\s+to\s+Delist\s+Results\s+and\s+Will\s+Delist\s+(?P<pairs>.+?)` + onDate,
+	},
+	{
+		Name:   "futures-delist",
+		Market: MarketFutures,
+		Regex:  `(?i)Binance\s+Futures\s+Will\s+(?:Delist|Close)\s+(?:(?:the\s+)?USDⓈ-M\s+)?(?P<pairs>.+?)\s+(?:USDⓈ-M\s+)?Perpetual\s+Contracts?` + optDate,
+	},
+	{
+		Name:   "margin-delist",
+		Market: MarketMargin,
+		Regex:  `(?i)Binance\s+Margin\s+Will\s+(?:Delist|Remove)\s+(?P<pairs>.+?)\s+(?:(?:Cross|Isolated)\s+Margin\s+(?:(?:&|and)\s+(?:Cross|Isolated)\s+Margin\s+)?)?(?:Trading\s+)?Pairs?` + optDate,
+	},
+	{
+		Name:   "cease-trading",
+		Market: MarketSpot,
+		Regex:  `(?i)Binance(?:\s+(?P<scope>Futures|Margin))?\s+Will\s+Cease\s+Trading\s+(?:on|of|for)\s+(?:the\s+)?(?P<pairs>.+?)(?:\s+(?:Spot\s+)?(?:Trading\s+Pairs?|Perpetual\s+Contracts?))?` + onDate,
+	},
+	{
+		Name:        "spot-pair-removal",
+		Market:      MarketSpot,
+		Regex:       `(?i)Notice\s+(?:of|on)\s+Removal\s+of\s+Spot\s+Trading\s+Pairs` + optDate,
+		PairsInBody: true,
+	},
+	{
+		Name:   "remove-spot-pairs",
+		Market: MarketSpot,
+		Regex:  `(?i)Binance\s+Will\s+Remove\s+(?P<pairs>.+?)\s+(?:Spot\s+)?Trading\s+Pairs?` + optDate,
+	},
+	{
+		Name:   "will-delist",
+		Market: MarketSpot,
+		Regex:  `(?i)Binance\s+Will\s+Delist\s+(?P<pairs>.+?)` + onDate,
+	},
+	{
+		Name:   "will-delist-undated",
+		Market: MarketSpot,
+		Regex:  `(?i)Binance\s+Will\s+Delist\s+` + tickers + updated + `\s*$`,
+	},
+}
+
+var (
+	spotTitle    = regexp.MustCompile(`(?i)\bspot\b`)
+	futuresTitle = regexp.MustCompile(`(?i)USDⓈ-M|COIN-M|futures|perpetual|contracts?\b`)
+	marginTitle  = regexp.MustCompile(`(?i)\bmargin\b`)
+	tickerLike   = regexp.MustCompile(`^[A-Z0-9]{2,20}$`)
+)
+
+var ordinals = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+}
+
+// ParseAnnouncement parses a Binance delisting title into pairs, delisting
+// time and affected markets using the active rules (see SetRules and
+// WatchRules). Titles no rule matches are kept for UnmatchedTitles.
+//
+// Example:
+//
+//	"Binance Will Delist BTCUSDT, ETHUSDT on 2025-10-31"
+//	→ Pairs [BTCUSDT ETHUSDT], DelistAt 2025-10-31 00:00 UTC, Markets [spot]
+func ParseAnnouncement(title string) (*Announcement, error) {
+	a, err := Rules().Parse(title)
+	if errors.Is(err, ErrNoMatch) {
+		recordUnmatched(title)
+	}
+	return a, err
+}
+
+// Parse matches title against the rules in order.
+func (rs *RuleSet) Parse(title string) (*Announcement, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, ErrNoMatch
+	}
+
+	for _, r := range rs.rules {
+		m := r.re.FindStringSubmatch(title)
+		if m == nil {
+			continue
+		}
+		group := func(role string) string {
+			if i := r.groups[role]; i > 0 {
+				return m[i]
+			}
+			return ""
+		}
+
+		market := r.Market
+		if scope := group("scope"); scope != "" {
+			market = Market(strings.ToLower(scope))
+		}
+		a := &Announcement{
+			Title:   title,
+			Pairs:   cleanPairs(group("pairs")),
+			Pattern: r.Name,
+			Markets: TitleMarkets(title, market),
+		}
+		if len(a.Pairs) == 0 && !r.PairsInBody {
+			continue
+		}
+		if err := a.setTime(group("date"), group("time")); err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", r.Name, err)
+		}
+		a.Batch = batchNumber(group("batch"))
+		a.Confidence = confidence(a)
+		return a, nil
+	}
+	return nil, ErrNoMatch
 }
 
 // ExtractPairs extracts trading pairs (e.g., "BTCUSDT", "ETHUSDT") from Binance delisting announcement titles.
@@ -20,38 +171,124 @@ var delistPatterns = []*regexp.Regexp{
 //	"Binance Will Delist BTCUSDT, ETHUSDT on 2025-10-31"
 //	→ ["BTCUSDT", "ETHUSDT"]
 func ExtractPairs(title string) []string {
-	title = strings.TrimSpace(title)
-	if title == "" {
+	a, err := ParseAnnouncement(title)
+	if err != nil {
+		if !errors.Is(err, ErrNoMatch) {
+			log.Printf("Parsing announcement %q: %v", strings.TrimSpace(title), err)
+		}
 		return nil
 	}
+	return a.Pairs
+}
 
-	for _, pattern := range delistPatterns {
-		if matches := pattern.FindStringSubmatch(title); len(matches) > 1 {
-			return cleanPairs(matches[1])
+func (a *Announcement) setTime(date, clock string) error {
+	if date == "" {
+		return nil
+	}
+	layout, value := "2006-01-02", date
+	if clock != "" {
+		layout, value = "2006-01-02 15:04", date+" "+clock
+		if len(clock) == 4 { // "3:00"
+			layout = "2006-01-02 3:04"
 		}
 	}
-
-	fmt.Printf("[%s] ⚠️  No known delisting pattern matched: %q\n", timestamp(), title)
+	t, err := time.ParseInLocation(layout, value, time.UTC)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", value, err)
+	}
+	a.DelistAt = t
+	a.HasTime = clock != ""
 	return nil
 }
 
-// cleanPairs splits a string of comma-separated pairs and trims spaces.
-func cleanPairs(input string) []string {
-	// Handles commas, semicolons, or multiple spaces as delimiters.
-	fields := strings.FieldsFunc(input, func(r rune) bool {
-		return r == ',' || r == ';'
-	})
+// TitleMarkets returns the base markets plus the markets a title names,
+// in spot, margin, futures order.
+func TitleMarkets(title string, base ...Market) []Market {
+	has := make(map[Market]bool, len(base)+3)
+	for _, m := range base {
+		has[m] = true
+	}
+	has[MarketSpot] = has[MarketSpot] || spotTitle.MatchString(title)
+	has[MarketMargin] = has[MarketMargin] || marginTitle.MatchString(title)
+	has[MarketFutures] = has[MarketFutures] || futuresTitle.MatchString(title)
 
-	var pairs []string
-	for _, f := range fields {
-		if trimmed := strings.TrimSpace(f); trimmed != "" {
-			pairs = append(pairs, trimmed)
+	var out []Market
+	for _, m := range []Market{MarketSpot, MarketMargin, MarketFutures} {
+		if has[m] {
+			out = append(out, m)
 		}
 	}
-	return pairs
+	return out
+}
+
+func batchNumber(s string) int {
+	if s == "" {
+		return 0
+	}
+	if n, ok := ordinals[strings.ToLower(s)]; ok {
+		return n
+	}
+	n, _ := strconv.Atoi(strings.TrimRight(strings.ToLower(s), "stndrh"))
+	return n
 }
 
-// timestamp returns a formatted timestamp string.
-func timestamp() string {
-	return time.Now().Format("2006-01-02 15:04:05")
+// confidence scores a match: a date, a time of day and ticker-shaped
+// pairs each make it more trustworthy. Titles without pairs score low,
+// since the body has to be read before trading.
+func confidence(a *Announcement) float64 {
+	c := 0.5
+	if !a.DelistAt.IsZero() {
+		c += 0.2
+	}
+	if a.HasTime {
+		c += 0.1
+	}
+	clean := len(a.Pairs) > 0
+	for _, p := range a.Pairs {
+		if !tickerLike.MatchString(p) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		c += 0.2
+	} else if len(a.Pairs) == 0 {
+		c -= 0.2
+	}
+	return math.Round(c*100) / 100
+}
+
+var (
+	pairSeparator = regexp.MustCompile(`\s*(?:,|;|&|\s+and\s+)\s*`)
+	// "Multichain (MULTI)" → "MULTI"
+	namedTicker = regexp.MustCompile(`\(([A-Za-z0-9]{2,15})\)`)
+	slashPair   = regexp.MustCompile(`^([A-Za-z0-9]{2,15})\s*/\s*([A-Za-z]{3,5})$`)
+)
+
+// Symbol joins a base and quote asset into the Binance symbol form:
+// Symbol("abc", "USDT") is "ABCUSDT".
+func Symbol(base, quote string) string {
+	return strings.ToUpper(base + quote)
+}
+
+// cleanPairs splits a list of tokens or pairs on commas, semicolons, "&"
+// and "and", keeps the ticker of "Name (TICKER)" and joins slashed pairs
+// ("ABC/USDT") into the Binance symbol form ("ABCUSDT").
+func cleanPairs(input string) []string {
+	var pairs []string
+	seen := make(map[string]bool)
+	for _, f := range pairSeparator.Split(strings.TrimSpace(input), -1) {
+		f = strings.TrimSpace(f)
+		if m := namedTicker.FindStringSubmatch(f); m != nil {
+			f = m[1]
+		}
+		if m := slashPair.FindStringSubmatch(f); m != nil {
+			f = Symbol(m[1], m[2])
+		}
+		if f != "" && !seen[f] {
+			seen[f] = true
+			pairs = append(pairs, f)
+		}
+	}
+	return pairs
 }
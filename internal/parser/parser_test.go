@@ -0,0 +1,289 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// titleCase is a title and what it means.
+type titleCase struct {
+	title   string
+	pattern string
+	pairs   []string
+	date    string // "2006-01-02" or "2006-01-02 15:04", "" for none
+	markets []Market
+	batch   int
+}
+
+// titleCorpus holds titles copied from Binance announcements. The shipped
+// rules must cover all of them; see TestShippedRulesMatchDefaults.
+var titleCorpus = []titleCase{
+	{
+		title:   "Binance Will Delist BADGER, BAL, BETA, CREAM, CVX, IRIS, KP3R, OOKI, REN, SNT and UNFI on 2024-04-16",
+		pattern: "will-delist",
+		pairs:   []string{"BADGER", "BAL", "BETA", "CREAM", "CVX", "IRIS", "KP3R", "OOKI", "REN", "SNT", "UNFI"},
+		date:    "2024-04-16",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Delist CVP, EPX, FOR, LOOM, REEF, VGX on 2024-08-26",
+		pattern: "will-delist",
+		pairs:   []string{"CVP", "EPX", "FOR", "LOOM", "REEF", "VGX"},
+		date:    "2024-08-26",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Delist ANT, MULTI, VAI, XMR on 2024-02-20",
+		pattern: "will-delist",
+		pairs:   []string{"ANT", "MULTI", "VAI", "XMR"},
+		date:    "2024-02-20",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Delist Multichain (MULTI) & Mdex (MDX) on 2023-07-14 03:00 (UTC)",
+		pattern: "will-delist",
+		pairs:   []string{"MULTI", "MDX"},
+		date:    "2023-07-14 03:00",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Announced the Second Batch of Vote to Delist Results and Will Delist BSW, CHESS and VIB on 2025-04-16",
+		pattern: "vote-to-delist",
+		pairs:   []string{"BSW", "CHESS", "VIB"},
+		date:    "2025-04-16",
+		markets: []Market{MarketSpot},
+		batch:   2,
+	},
+	{
+		title:   "Binance Announced the 3rd Batch of Vote to Delist Results and Will Delist ALPACA on 2025-05-02",
+		pattern: "vote-to-delist",
+		pairs:   []string{"ALPACA"},
+		date:    "2025-05-02",
+		markets: []Market{MarketSpot},
+		batch:   3,
+	},
+	{
+		title:   "Notice of Removal of Spot Trading Pairs - 2024-05-03",
+		pattern: "spot-pair-removal",
+		date:    "2024-05-03",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Notice on Removal of Spot Trading Pairs - 2023-09-08",
+		pattern: "spot-pair-removal",
+		date:    "2023-09-08",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Remove ANKR/ETH, CTK/BUSD & OGN/BTC Spot Trading Pairs on 2024-01-05",
+		pattern: "remove-spot-pairs",
+		pairs:   []string{"ANKRETH", "CTKBUSD", "OGNBTC"},
+		date:    "2024-01-05",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Futures Will Delist USDⓈ-M BNXUSDT Perpetual Contract",
+		pattern: "futures-delist",
+		pairs:   []string{"BNXUSDT"},
+		markets: []Market{MarketFutures},
+	},
+	{
+		title:   "Binance Futures Will Delist USDⓈ-M SCUSDT and REEFUSDT Perpetual Contracts",
+		pattern: "futures-delist",
+		pairs:   []string{"SCUSDT", "REEFUSDT"},
+		markets: []Market{MarketFutures},
+	},
+	{
+		title:   "Binance Futures Will Delist USDⓈ-M COMBOUSDT, ORBSUSDT & AMBUSDT Perpetual Contracts on 2024-08-12",
+		pattern: "futures-delist",
+		pairs:   []string{"COMBOUSDT", "ORBSUSDT", "AMBUSDT"},
+		date:    "2024-08-12",
+		markets: []Market{MarketFutures},
+	},
+	{
+		title:   "Binance Futures Will Close USDⓈ-M FTTUSDT Perpetual Contract on 2022-11-14 at 13:00 (UTC)",
+		pattern: "futures-delist",
+		pairs:   []string{"FTTUSDT"},
+		date:    "2022-11-14 13:00",
+		markets: []Market{MarketFutures},
+	},
+	{
+		title:   "Binance Futures Will Cease Trading of BTCDOMUSDT Perpetual Contracts on 2024-03-01",
+		pattern: "cease-trading",
+		pairs:   []string{"BTCDOMUSDT"},
+		date:    "2024-03-01",
+		markets: []Market{MarketFutures},
+	},
+	{
+		title:   "Binance Margin Will Delist BAL/BTC, CVX/USDT Cross Margin & Isolated Margin Pairs",
+		pattern: "margin-delist",
+		pairs:   []string{"BALBTC", "CVXUSDT"},
+		markets: []Market{MarketMargin},
+	},
+	{
+		title:   "Binance Margin Will Delist ARDR/BTC and DCR/USDT Isolated Margin Pairs on 2024-03-13",
+		pattern: "margin-delist",
+		pairs:   []string{"ARDRBTC", "DCRUSDT"},
+		date:    "2024-03-13",
+		markets: []Market{MarketMargin},
+	},
+	{
+		title:   "Binance Margin Will Remove OOKI/USDT Cross Margin and Isolated Margin Pairs",
+		pattern: "margin-delist",
+		pairs:   []string{"OOKIUSDT"},
+		markets: []Market{MarketMargin},
+	},
+	{
+		title:   "Binance Will Delist WAVES, OMG, WNXM, XEM",
+		pattern: "will-delist-undated",
+		pairs:   []string{"WAVES", "OMG", "WNXM", "XEM"},
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Delist Multichain (MULTI) (Updated)",
+		pattern: "will-delist-undated",
+		pairs:   []string{"MULTI"},
+		markets: []Market{MarketSpot},
+	},
+}
+
+// ruleCases are made-up titles in the shape of one rule, for variations
+// the corpus lacks. They are not evidence of coverage.
+var ruleCases = []titleCase{
+	{
+		title:   "Binance Will Delist BTCUSDT, ETHUSDT, XRPUSDT on 2025-10-31",
+		pattern: "will-delist",
+		pairs:   []string{"BTCUSDT", "ETHUSDT", "XRPUSDT"},
+		date:    "2025-10-31",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Announced the First Batch of Vote to Delist Results and Will Delist SOLUSDT, ADAUSDT on 2025-12-01",
+		pattern: "vote-to-delist",
+		pairs:   []string{"SOLUSDT", "ADAUSDT"},
+		date:    "2025-12-01",
+		markets: []Market{MarketSpot},
+		batch:   1,
+	},
+	{
+		title:   "Binance Will Cease Trading on ABC/BTC, DEF/USDT Spot Trading Pairs on 2023-06-09",
+		pattern: "cease-trading",
+		pairs:   []string{"ABCBTC", "DEFUSDT"},
+		date:    "2023-06-09",
+		markets: []Market{MarketSpot},
+	},
+	{
+		title:   "Binance Will Delist ANT, MULTI, VAI & XMR (Updated)",
+		pattern: "will-delist-undated",
+		pairs:   []string{"ANT", "MULTI", "VAI", "XMR"},
+		markets: []Market{MarketSpot},
+	},
+}
+
+func TestParseAnnouncementCorpus(t *testing.T) {
+	for _, tc := range slices.Concat(titleCorpus, ruleCases) {
+		t.Run(tc.title, func(t *testing.T) {
+			a, err := ParseAnnouncement(tc.title)
+			if err != nil {
+				t.Fatalf("ParseAnnouncement: %v", err)
+			}
+			if a.Pattern != tc.pattern {
+				t.Errorf("pattern = %q, want %q", a.Pattern, tc.pattern)
+			}
+			if !reflect.DeepEqual(a.Pairs, tc.pairs) {
+				t.Errorf("pairs = %q, want %q", a.Pairs, tc.pairs)
+			}
+			if !reflect.DeepEqual(a.Markets, tc.markets) {
+				t.Errorf("markets = %v, want %v", a.Markets, tc.markets)
+			}
+			if a.Batch != tc.batch {
+				t.Errorf("batch = %d, want %d", a.Batch, tc.batch)
+			}
+
+			var want time.Time
+			switch len(tc.date) {
+			case 10:
+				want, _ = time.Parse("2006-01-02", tc.date)
+			case 16:
+				want, _ = time.Parse("2006-01-02 15:04", tc.date)
+			}
+			if !a.DelistAt.Equal(want) || a.DelistAt.Location() != time.UTC {
+				t.Errorf("delist at = %v, want %v UTC", a.DelistAt, want)
+			}
+			if a.HasTime != (len(tc.date) == 16) {
+				t.Errorf("has time = %v for %q", a.HasTime, tc.date)
+			}
+			if a.Confidence <= 0 || a.Confidence > 1 {
+				t.Errorf("confidence = %v out of range", a.Confidence)
+			}
+		})
+	}
+}
+
+func TestParseAnnouncementNoMatch(t *testing.T) {
+	for _, title := range []string{
+		"",
+		"Random Non-Matching Title",
+		"Binance Will List Ethena (ENA) with Seed Tag Applied",
+		"Binance Futures Will Launch USDⓈ-M ZRO Perpetual Contract With Up to 50x Leverage",
+		"Binance Will Extend the Monitoring Tag to Include ABC, DEF",
+		"Binance Will Delist ABC Perpetual Contract",
+		"Binance Will Delist Some Tokens",
+		"Binance Will Delist Tokens in the Innovation Zone (Updated)",
+	} {
+		if a, err := ParseAnnouncement(title); !errors.Is(err, ErrNoMatch) {
+			t.Errorf("ParseAnnouncement(%q) = %+v, %v; want ErrNoMatch", title, a, err)
+		}
+	}
+}
+
+func TestParseAnnouncementInvalidDate(t *testing.T) {
+	_, err := ParseAnnouncement("Binance Will Delist ABC on 2025-13-01")
+	if err == nil || errors.Is(err, ErrNoMatch) {
+		t.Fatalf("want a date error, got %v", err)
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	dated, _ := ParseAnnouncement("Binance Will Delist ABC on 2025-01-01 03:00 (UTC)")
+	undated, _ := ParseAnnouncement("Binance Will Delist ABC, DEF")
+	bodyOnly, _ := ParseAnnouncement("Notice of Removal of Spot Trading Pairs - 2024-05-03")
+
+	if !(dated.Confidence > undated.Confidence && undated.Confidence > bodyOnly.Confidence) {
+		t.Errorf("confidence order: dated %v, undated %v, body-only %v",
+			dated.Confidence, undated.Confidence, bodyOnly.Confidence)
+	}
+}
+
+func TestCleanPairs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"BTCUSDT, ETHUSDT", []string{"BTCUSDT", "ETHUSDT"}},
+		{"A, B and C", []string{"A", "B", "C"}},
+		{"A & B; C", []string{"A", "B", "C"}},
+		{"ABC/USDT, DEF / BTC", []string{"ABCUSDT", "DEFBTC"}},
+		{"Multichain (MULTI) and Mdex (MDX)", []string{"MULTI", "MDX"}},
+		{"A, A", []string{"A"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := cleanPairs(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cleanPairs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtractPairs(t *testing.T) {
+	got := ExtractPairs("Binance Will Delist BTCUSDT, ETHUSDT on 2025-10-31")
+	if want := []string{"BTCUSDT", "ETHUSDT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPairs = %q, want %q", got, want)
+	}
+	if got := ExtractPairs("Random Non-Matching Title"); got != nil {
+		t.Errorf("ExtractPairs on unmatched title = %q, want nil", got)
+	}
+}
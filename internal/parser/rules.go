@@ -0,0 +1,338 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ====== RULES ======
+
+// Rule is one title pattern as written in a rules file:
+//
+//	{"rules": [{
+//	  "name": "will-delist",
+//	  "regex": "(?i)Binance\\s+Will\\s+Delist\\s+(?P<pairs>.+?)\\s+on\\s+(?P<date>\\d{4}-\\d{2}-\\d{2})",
+//	  "market": "spot"
+//	}]}
+//
+// Captures maps a role (pairs, date, time, batch, scope) to a named group
+// or group number; roles left out use the group of the same name. "pairs"
+// is required unless PairsInBody is set, for titles that never list pairs.
+type Rule struct {
+	Name        string            `json:"name"`
+	Regex       string            `json:"regex"`
+	Market      Market            `json:"market"`
+	Captures    map[string]string `json:"captures,omitempty"`
+	PairsInBody bool              `json:"pairsInBody,omitempty"`
+}
+
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// captureRoles are the groups Parse understands.
+var captureRoles = []string{"pairs", "date", "time", "batch", "scope"}
+
+type compiledRule struct {
+	Rule
+	re     *regexp.Regexp
+	groups map[string]int // role -> submatch index
+}
+
+// RuleSet is a validated, compiled list of rules.
+type RuleSet struct {
+	rules  []compiledRule
+	Source string // file path, or "built-in"
+	Loaded time.Time
+}
+
+var active atomic.Pointer[RuleSet]
+
+func init() {
+	rs, err := CompileRules(defaultRules)
+	if err != nil {
+		panic(fmt.Sprintf("built-in parser rules: %v", err))
+	}
+	rs.Source = "built-in"
+	active.Store(rs)
+}
+
+// DefaultRules returns the built-in rules, e.g. to seed a rules file.
+func DefaultRules() []Rule {
+	return append([]Rule(nil), defaultRules...)
+}
+
+// Rules returns the active rule set.
+func Rules() *RuleSet {
+	return active.Load()
+}
+
+// SetRules replaces the active rule set.
+func SetRules(rs *RuleSet) {
+	active.Store(rs)
+}
+
+// Names lists the rule names in match order.
+func (rs *RuleSet) Names() []string {
+	names := make([]string, len(rs.rules))
+	for i, r := range rs.rules {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// ====== VALIDATION ======
+
+// CompileRules validates and compiles rules. Every problem is reported,
+// not just the first.
+func CompileRules(rules []Rule) (*RuleSet, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("no rules")
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	rs := &RuleSet{Loaded: time.Now()}
+
+	for i, r := range rules {
+		label := r.Name
+		if label == "" {
+			label = "#" + strconv.Itoa(i+1)
+			errs = append(errs, fmt.Errorf("rule %s: missing name", label))
+		} else if seen[r.Name] {
+			errs = append(errs, fmt.Errorf("rule %s: duplicate name", label))
+		}
+		seen[r.Name] = true
+
+		switch r.Market {
+		case MarketSpot, MarketFutures, MarketMargin:
+		default:
+			errs = append(errs, fmt.Errorf("rule %s: unknown market %q (want spot, futures or margin)", label, r.Market))
+		}
+
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", label, err))
+			continue
+		}
+
+		groups, err := resolveGroups(re, r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", label, err))
+			continue
+		}
+		rs.rules = append(rs.rules, compiledRule{Rule: r, re: re, groups: groups})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return rs, nil
+}
+
+func resolveGroups(re *regexp.Regexp, r Rule) (map[string]int, error) {
+	known := make(map[string]bool, len(captureRoles))
+	for _, role := range captureRoles {
+		known[role] = true
+	}
+	for role := range r.Captures {
+		if !known[role] {
+			return nil, fmt.Errorf("unknown capture role %q", role)
+		}
+	}
+
+	groups := make(map[string]int)
+	for _, role := range captureRoles {
+		ref, explicit := r.Captures[role]
+		if !explicit {
+			ref = role
+		}
+
+		idx := -1
+		if n, err := strconv.Atoi(ref); err == nil {
+			if n < 1 || n > re.NumSubexp() {
+				return nil, fmt.Errorf("capture %s: group %d out of range", role, n)
+			}
+			idx = n
+		} else {
+			idx = re.SubexpIndex(ref)
+		}
+
+		switch {
+		case idx > 0:
+			groups[role] = idx
+		case explicit:
+			return nil, fmt.Errorf("capture %s: no group %q", role, ref)
+		}
+	}
+
+	if _, ok := groups["pairs"]; !ok && !r.PairsInBody {
+		return nil, errors.New(`no "pairs" group; set pairsInBody for titles without pairs`)
+	}
+	return groups, nil
+}
+
+// ====== LOADING ======
+
+// LoadRules reads and validates a JSON rules file.
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+
+	var f rulesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("decode rules %s: %w", path, err)
+	}
+
+	rs, err := CompileRules(f.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("rules %s: %w", path, err)
+	}
+	rs.Source = path
+	return rs, nil
+}
+
+// WatchRules loads path, makes it the active rule set and reloads it
+// whenever the file changes, until ctx is cancelled. An invalid initial
+// file is returned as an error; an invalid edit is logged and the
+// previous rules stay active.
+func WatchRules(ctx context.Context, path string, interval time.Duration) error {
+	return watchRules(ctx, path, interval, SetRules, nil)
+}
+
+// watchRules is WatchRules with the rule set handed to apply. reloaded, if
+// set, is called after every reload attempt with its error.
+func watchRules(ctx context.Context, path string, interval time.Duration, apply func(*RuleSet), reloaded func(error)) error {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	rs, err := LoadRules(path)
+	if err != nil {
+		return err
+	}
+	apply(rs)
+	log.Printf("Loaded %d parser rules from %s", len(rs.rules), path)
+
+	last, _ := os.Stat(path)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				log.Printf("Parser rules %s unavailable, keeping current rules: %v", path, err)
+				continue
+			}
+			if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			last = info
+
+			rs, err := LoadRules(path)
+			if err != nil {
+				log.Printf("Parser rules reload failed, keeping current rules: %v", err)
+			} else {
+				apply(rs)
+				log.Printf("Reloaded %d parser rules from %s", len(rs.rules), path)
+			}
+			if reloaded != nil {
+				reloaded(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// ====== UNMATCHED TITLES ======
+
+// UnmatchedTitle is a title no rule caught.
+type UnmatchedTitle struct {
+	Title     string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+const maxUnmatched = 500
+
+var (
+	unmatched   = make(map[string]*UnmatchedTitle)
+	unmatchedMu sync.Mutex
+)
+
+func recordUnmatched(title string) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return
+	}
+	now := time.Now()
+
+	unmatchedMu.Lock()
+	defer unmatchedMu.Unlock()
+
+	if u, ok := unmatched[title]; ok {
+		u.Count++
+		u.LastSeen = now
+		return
+	}
+	if len(unmatched) >= maxUnmatched {
+		return
+	}
+	unmatched[title] = &UnmatchedTitle{Title: title, Count: 1, FirstSeen: now, LastSeen: now}
+}
+
+// UnmatchedTitles reports the titles ParseAnnouncement could not match
+// since start or the last ResetUnmatched, most recent first.
+func UnmatchedTitles() []UnmatchedTitle {
+	unmatchedMu.Lock()
+	defer unmatchedMu.Unlock()
+
+	out := make([]UnmatchedTitle, 0, len(unmatched))
+	for _, u := range unmatched {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastSeen.After(out[j].LastSeen) })
+	return out
+}
+
+func ResetUnmatched() {
+	unmatchedMu.Lock()
+	defer unmatchedMu.Unlock()
+	unmatched = make(map[string]*UnmatchedTitle)
+}
+
+// Unmatched returns the titles no rule in rs catches, e.g. to check a
+// rules file against a corpus before deploying it.
+func (rs *RuleSet) Unmatched(titles []string) []string {
+	var out []string
+	for _, t := range titles {
+		if _, err := rs.Parse(t); errors.Is(err, ErrNoMatch) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShippedRulesMatchDefaults(t *testing.T) {
+	const path = "../../config/parser_rules.json"
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f rulesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	defaults := DefaultRules()
+	if len(f.Rules) != len(defaults) {
+		t.Fatalf("shipped %d rules, built-in %d", len(f.Rules), len(defaults))
+	}
+	for i, want := range defaults {
+		if got := f.Rules[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("shipped rule %d = %+v\nbuilt-in %+v", i, got, want)
+		}
+	}
+
+	rs, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+
+	titles := make([]string, len(titleCorpus))
+	for i, tc := range titleCorpus {
+		titles[i] = tc.title
+	}
+	if missed := rs.Unmatched(titles); len(missed) > 0 {
+		t.Errorf("shipped rules miss %q", missed)
+	}
+}
+
+func TestCompileRulesValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"no name", Rule{Regex: `(?P<pairs>\w+)`, Market: MarketSpot}, "missing name"},
+		{"bad regex", Rule{Name: "r", Regex: `(?P<pairs>\w+`, Market: MarketSpot}, "missing closing )"},
+		{"bad market", Rule{Name: "r", Regex: `(?P<pairs>\w+)`, Market: "options"}, "unknown market"},
+		{"no pairs", Rule{Name: "r", Regex: `Delist (\w+)`, Market: MarketSpot}, `no "pairs" group`},
+		{"unknown role", Rule{Name: "r", Regex: `(?P<pairs>\w+)`, Market: MarketSpot, Captures: map[string]string{"token": "pairs"}}, "unknown capture role"},
+		{"missing group", Rule{Name: "r", Regex: `(?P<pairs>\w+)`, Market: MarketSpot, Captures: map[string]string{"date": "day"}}, `no group "day"`},
+		{"group out of range", Rule{Name: "r", Regex: `(\w+)`, Market: MarketSpot, Captures: map[string]string{"pairs": "2"}}, "out of range"},
+	}
+	for _, tt := range tests {
+		_, err := CompileRules([]Rule{tt.rule})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	_, err := CompileRules([]Rule{
+		{Name: "dup", Regex: `(?P<pairs>\w+)`, Market: MarketSpot},
+		{Name: "dup", Regex: `(?P<pairs>\w+)`, Market: MarketSpot},
+	})
+	if err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("duplicate: err = %v", err)
+	}
+}
+
+func TestNumberedCaptures(t *testing.T) {
+	rs, err := CompileRules([]Rule{{
+		Name:     "numbered",
+		Regex:    `(?i)Delisting (\S+) at (\d{4}-\d{2}-\d{2})`,
+		Market:   MarketFutures,
+		Captures: map[string]string{"pairs": "1", "date": "2"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := rs.Parse("Delisting ABCUSDT at 2025-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.Pairs, []string{"ABCUSDT"}) || a.DelistAt.Format("2006-01-02") != "2025-01-02" {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestWatchRulesReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"rules":[{"name":"one","regex":"(?i)Gone (?P<pairs>\\w+)","market":"spot"}]}`)
+
+	var current atomic.Pointer[RuleSet]
+	reloads := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := watchRules(ctx, path, time.Millisecond, current.Store, func(err error) { reloads <- err }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.Load().Parse("Gone ABC"); err != nil {
+		t.Fatalf("initial rules: %v", err)
+	}
+	reload := func() error {
+		t.Helper()
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("rules file change not picked up")
+			return nil
+		}
+	}
+
+	// An invalid edit keeps the current rules.
+	write(`{"rules":[{"name":"one","regex":"(","market":"spot"}]}`)
+	if err := reload(); err == nil {
+		t.Fatal("invalid rules reloaded without error")
+	}
+	if got := current.Load().Names(); !reflect.DeepEqual(got, []string{"one"}) {
+		t.Fatalf("after invalid edit: %v", got)
+	}
+
+	write(`{"rules":[{"name":"two","regex":"(?i)Removed (?P<pairs>\\w+)","market":"margin"}]}`)
+	// A reload may still report the invalid edit, or catch the file half
+	// written; wait for the one that succeeds.
+	for reload() != nil {
+	}
+	rs := current.Load()
+	if got := rs.Names(); !reflect.DeepEqual(got, []string{"two"}) {
+		t.Fatalf("after valid edit: %v", got)
+	}
+	if _, err := rs.Parse("Gone ABC"); err == nil {
+		t.Fatal("old rule still active")
+	}
+}
+
+func TestUnmatchedTitles(t *testing.T) {
+	ResetUnmatched()
+	t.Cleanup(ResetUnmatched)
+	for range 2 {
+		if _, err := ParseAnnouncement("Gone ABC"); err == nil {
+			t.Fatal("built-in rules matched a made-up title")
+		}
+	}
+	u := UnmatchedTitles()
+	if len(u) != 1 || u[0].Title != "Gone ABC" || u[0].Count != 2 {
+		t.Errorf("unmatched = %+v", u)
+	}
+}
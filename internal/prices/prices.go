@@ -0,0 +1,226 @@
+package prices
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"bingxGo/internal/bingx"
+	"bingxGo/internal/websocket"
+)
+
+// ====== TYPES ======
+
+// Quote is the latest known state of a symbol. Each value carries the time
+// it was last updated; zero times mean the value was never seen.
+type Quote struct {
+	Symbol          string
+	Last            float64
+	LastUpdated     time.Time
+	Mark            float64
+	MarkUpdated     time.Time
+	Index           float64
+	IndexUpdated    time.Time
+	FundingRate     float64
+	NextFundingTime time.Time
+	FundingUpdated  time.Time
+}
+
+// Store is a concurrent-safe last-price cache shared by sizing, PnL and
+// alert code.
+type Store struct {
+	quotes map[string]Quote
+	subs   map[int]*subscription
+	nextID int
+	mu     sync.RWMutex
+}
+
+type subscription struct {
+	symbols map[string]bool // empty means all symbols
+	ch      chan Quote
+}
+
+// ====== CONSTRUCTOR ======
+
+func NewStore() *Store {
+	return &Store{
+		quotes: make(map[string]Quote),
+		subs:   make(map[int]*subscription),
+	}
+}
+
+// ====== READS ======
+
+func (s *Store) Get(symbol string) (Quote, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	q, ok := s.quotes[symbol]
+	return q, ok
+}
+
+// Snapshot returns a consistent copy of every quote.
+func (s *Store) Snapshot() map[string]Quote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]Quote, len(s.quotes))
+	for k, v := range s.quotes {
+		out[k] = v
+	}
+	return out
+}
+
+// Subscribe returns a channel receiving the updated quote whenever one of
+// symbols (or any symbol, if none given) changes, and a cancel function.
+// Sends never block the feed: when the buffer is full the change is dropped
+// and the reader can fall back to Get.
+func (s *Store) Subscribe(buffer int, symbols ...string) (<-chan Quote, func()) {
+	sub := &subscription{
+		symbols: make(map[string]bool, len(symbols)),
+		ch:      make(chan Quote, buffer),
+	}
+	for _, sym := range symbols {
+		sub.symbols[sym] = true
+	}
+
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.subs[id] = sub
+	s.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, id)
+			s.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+	return sub.ch, cancel
+}
+
+// ====== WRITES ======
+
+func (s *Store) SetLast(symbol string, price float64, at time.Time) {
+	s.update(symbol, func(q *Quote) bool {
+		changed := q.Last != price
+		q.Last, q.LastUpdated = price, at
+		return changed
+	})
+}
+
+func (s *Store) SetMark(symbol string, price float64, at time.Time) {
+	s.update(symbol, func(q *Quote) bool {
+		changed := q.Mark != price
+		q.Mark, q.MarkUpdated = price, at
+		return changed
+	})
+}
+
+// update applies fn to the quote and notifies subscribers when fn reports
+// a change.
+func (s *Store) update(symbol string, fn func(q *Quote) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.quotes[symbol]
+	q.Symbol = symbol
+	changed := fn(&q)
+	s.quotes[symbol] = q
+
+	if !changed {
+		return
+	}
+	for _, sub := range s.subs {
+		if len(sub.symbols) > 0 && !sub.symbols[symbol] {
+			continue
+		}
+		select {
+		case sub.ch <- q:
+		default:
+		}
+	}
+}
+
+// ====== FEEDS ======
+
+// Handlers feeds the store from a BingX WebSocket: mark and last price
+// streams, tickers and trades.
+func (s *Store) Handlers() websocket.Handlers {
+	return websocket.Handlers{
+		OnPrice: func(u websocket.PriceUpdate) {
+			if u.Stream == "markPrice" {
+				s.SetMark(u.Symbol, u.Price, time.Now())
+				return
+			}
+			s.SetLast(u.Symbol, u.Price, time.Now())
+		},
+		OnTicker: func(t websocket.Ticker) {
+			s.SetLast(t.Symbol, t.Last, eventTime(t.Time))
+		},
+		OnTrade: func(t websocket.Trade) {
+			s.SetLast(t.Symbol, t.Price, eventTime(t.Time))
+		},
+	}
+}
+
+// Refresh loads mark, index and funding for all symbols from
+// bingx.FetchPrices.
+func (s *Store) Refresh() error {
+	res, err := bingx.FetchPrices()
+	if err != nil {
+		return fmt.Errorf("refresh prices: %w", err)
+	}
+
+	for _, item := range res.Data {
+		mark, _ := strconv.ParseFloat(item.MarkPrice, 64)
+		index, _ := strconv.ParseFloat(item.IndexPrice, 64)
+		funding, _ := strconv.ParseFloat(item.LastFundingRate, 64)
+		at := eventTime(item.Time)
+
+		s.update(item.Symbol, func(q *Quote) bool {
+			changed := q.Index != index || q.FundingRate != funding
+			// The WebSocket mark stream is usually fresher than REST.
+			if at.After(q.MarkUpdated) {
+				changed = changed || q.Mark != mark
+				q.Mark, q.MarkUpdated = mark, at
+			}
+			q.Index, q.IndexUpdated = index, at
+			q.FundingRate, q.FundingUpdated = funding, at
+			if item.NextFundingTime > 0 {
+				q.NextFundingTime = time.UnixMilli(item.NextFundingTime)
+			}
+			return changed
+		})
+	}
+	return nil
+}
+
+// Run refreshes from REST every interval until quit is closed.
+func (s *Store) Run(interval time.Duration, quit <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// eventTime converts an exchange millisecond timestamp, defaulting to now.
+func eventTime(ms int64) time.Time {
+	if ms > 0 {
+		return time.UnixMilli(ms)
+	}
+	return time.Now()
+}
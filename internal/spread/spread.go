@@ -0,0 +1,331 @@
+package spread
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"bingxGo/internal/websocket"
+)
+
+// ====== CONFIG ======
+
+// PriceSource selects which price of a venue feeds the spread.
+type PriceSource string
+
+const (
+	PriceMark PriceSource = "mark" // mark price stream
+	PriceLast PriceSource = "last" // last price stream and trades
+	PriceMid  PriceSource = "mid"  // book ticker mid
+)
+
+type Config struct {
+	Window         time.Duration // time span of the rolling mean and deviation
+	SampleInterval time.Duration // at most one sample per interval, whatever the feed rate
+	BingXPrice     PriceSource   // default PriceMark
+	BinancePrice   PriceSource   // default PriceMark
+	MinSamples     int           // samples required before z-score alerts fire
+	ZThreshold     float64       // alert when |z| reaches this; 0 disables
+	AbsThreshold   float64       // alert when |spread| in percent reaches this; 0 disables
+	MaxAge         time.Duration // ignore a side whose price is older than this
+	Cooldown       time.Duration // minimum time between alerts per symbol
+	Notifier       websocket.Notifier
+	ChatID         string
+}
+
+const (
+	defaultWindow         = 5 * time.Minute
+	defaultSampleInterval = time.Second
+	defaultMinSamples     = 30
+	defaultMaxAge         = 5 * time.Second
+	defaultCooldown       = 5 * time.Minute
+)
+
+// ====== TYPES ======
+
+// Stat is the current spread state of a symbol. Spread is
+// (BingX - Binance) / Binance in percent; positive means BingX trades rich.
+type Stat struct {
+	Symbol  string
+	BingX   float64
+	Binance float64
+	Spread  float64
+	Mean    float64
+	StdDev  float64
+	Z       float64
+	Samples int
+	Updated time.Time
+}
+
+type quote struct {
+	price float64
+	at    time.Time
+}
+
+type sample struct {
+	at     time.Time
+	spread float64
+}
+
+// series is a time window of spread samples.
+type series struct {
+	samples    []sample
+	lastSample time.Time
+	bingx      quote
+	binance    quote
+	stat       Stat
+	lastAlert  time.Time
+}
+
+// Monitor tracks the BingX–Binance spread of every symbol listed on both.
+type Monitor struct {
+	cfg    Config
+	series map[string]*series
+	mu     sync.Mutex
+}
+
+// ====== CONSTRUCTOR ======
+
+func NewMonitor(cfg Config) *Monitor {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultWindow
+	}
+	if cfg.SampleInterval <= 0 {
+		cfg.SampleInterval = defaultSampleInterval
+	}
+	if cfg.BingXPrice == "" {
+		cfg.BingXPrice = PriceMark
+	}
+	if cfg.BinancePrice == "" {
+		cfg.BinancePrice = PriceMark
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultMinSamples
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultMaxAge
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultCooldown
+	}
+	return &Monitor{
+		cfg:    cfg,
+		series: make(map[string]*series),
+	}
+}
+
+// ====== FEEDS ======
+
+// BingXHandlers feeds the configured BingX price (Config.BingXPrice).
+func (m *Monitor) BingXHandlers() websocket.Handlers {
+	return handlers(m.cfg.BingXPrice, m.UpdateBingX)
+}
+
+// BinanceHandlers feeds the configured Binance price (Config.BinancePrice)
+// from binance.MarketStream.
+func (m *Monitor) BinanceHandlers() websocket.Handlers {
+	return handlers(m.cfg.BinancePrice, m.UpdateBinance)
+}
+
+// handlers passes on only the events of one price source, so a side's
+// series never mixes mark, trade and mid prices.
+func handlers(source PriceSource, update func(symbol string, price float64, at time.Time)) websocket.Handlers {
+	switch source {
+	case PriceLast:
+		return websocket.Handlers{
+			OnPrice: func(u websocket.PriceUpdate) {
+				if u.Stream == "lastPrice" {
+					update(u.Symbol, u.Price, time.Now())
+				}
+			},
+			OnTrade: func(t websocket.Trade) {
+				update(t.Symbol, t.Price, time.Now())
+			},
+		}
+	case PriceMid:
+		return websocket.Handlers{
+			OnBookTicker: func(b websocket.BookTicker) {
+				update(b.Symbol, (b.BidPrice+b.AskPrice)/2, time.Now())
+			},
+		}
+	default:
+		return websocket.Handlers{
+			OnPrice: func(u websocket.PriceUpdate) {
+				if u.Stream == "markPrice" {
+					update(u.Symbol, u.Price, time.Now())
+				}
+			},
+		}
+	}
+}
+
+func (m *Monitor) UpdateBingX(symbol string, price float64, at time.Time) {
+	m.update(symbol, func(s *series) { s.bingx = quote{price, at} })
+}
+
+func (m *Monitor) UpdateBinance(symbol string, price float64, at time.Time) {
+	m.update(symbol, func(s *series) { s.binance = quote{price, at} })
+}
+
+// ====== QUERIES ======
+
+func (m *Monitor) Get(symbol string) (Stat, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[Normalize(symbol)]
+	if !ok || len(s.samples) == 0 {
+		return Stat{}, false
+	}
+	return s.stat, true
+}
+
+// Stats returns the state of every symbol with at least one sample.
+func (m *Monitor) Stats() map[string]Stat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make(map[string]Stat, len(m.series))
+	for sym, s := range m.series {
+		if len(s.samples) > 0 {
+			out[sym] = s.stat
+		}
+	}
+	return out
+}
+
+// ====== SAMPLING ======
+
+func (m *Monitor) update(symbol string, set func(s *series)) {
+	sym := Normalize(symbol)
+
+	m.mu.Lock()
+	s, ok := m.series[sym]
+	if !ok {
+		s = &series{}
+		m.series[sym] = s
+	}
+	set(s)
+
+	now := time.Now()
+	if s.bingx.price <= 0 || s.binance.price <= 0 ||
+		now.Sub(s.bingx.at) > m.cfg.MaxAge || now.Sub(s.binance.at) > m.cfg.MaxAge {
+		m.mu.Unlock()
+		return
+	}
+
+	spread := (s.bingx.price - s.binance.price) / s.binance.price * 100
+
+	// Score against the window before this sample joins it. Sampling on
+	// a clock keeps the faster feed from dominating the window.
+	s.evict(now.Add(-m.cfg.Window))
+	mean, std := s.meanStd()
+	z := 0.0
+	if std > 0 {
+		z = (spread - mean) / std
+	}
+	if now.Sub(s.lastSample) >= m.cfg.SampleInterval {
+		s.add(now, spread)
+	}
+
+	s.stat = Stat{
+		Symbol:  sym,
+		BingX:   s.bingx.price,
+		Binance: s.binance.price,
+		Spread:  spread,
+		Mean:    mean,
+		StdDev:  std,
+		Z:       z,
+		Samples: len(s.samples),
+		Updated: now,
+	}
+
+	alert := m.shouldAlert(s, now)
+	stat := s.stat
+	m.mu.Unlock()
+
+	if alert {
+		m.notify(stat)
+	}
+}
+
+func (m *Monitor) shouldAlert(s *series, now time.Time) bool {
+	if now.Sub(s.lastAlert) < m.cfg.Cooldown {
+		return false
+	}
+
+	hit := false
+	if m.cfg.AbsThreshold > 0 && math.Abs(s.stat.Spread) >= m.cfg.AbsThreshold {
+		hit = true
+	}
+	if m.cfg.ZThreshold > 0 && len(s.samples) > m.cfg.MinSamples && math.Abs(s.stat.Z) >= m.cfg.ZThreshold {
+		hit = true
+	}
+	if hit {
+		s.lastAlert = now
+	}
+	return hit
+}
+
+func (s *series) add(at time.Time, v float64) {
+	s.samples = append(s.samples, sample{at, v})
+	s.lastSample = at
+}
+
+// evict drops samples taken before cutoff.
+func (s *series) evict(cutoff time.Time) {
+	n := 0
+	for n < len(s.samples) && s.samples[n].at.Before(cutoff) {
+		n++
+	}
+	if n > 0 {
+		s.samples = append(s.samples[:0], s.samples[n:]...)
+	}
+}
+
+// meanStd computes over the window directly; it holds at most
+// Window/SampleInterval samples.
+func (s *series) meanStd() (float64, float64) {
+	if len(s.samples) == 0 {
+		return 0, 0
+	}
+	n := float64(len(s.samples))
+	mean := 0.0
+	for _, p := range s.samples {
+		mean += p.spread
+	}
+	mean /= n
+	variance := 0.0
+	for _, p := range s.samples {
+		d := p.spread - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / n)
+}
+
+// ====== ALERTS ======
+
+func (m *Monitor) notify(st Stat) {
+	msg := fmt.Sprintf("📊 <b>Spread %s</b>: %+.3f%% (z=%.2f)\nBingX %g · Binance %g\nmean %+.3f%% ± %.3f%% over %d samples (%s)",
+		st.Symbol, st.Spread, st.Z, st.BingX, st.Binance, st.Mean, st.StdDev, st.Samples, m.cfg.Window)
+	log.Printf("Spread alert %s: %+.3f%% z=%.2f", st.Symbol, st.Spread, st.Z)
+
+	if m.cfg.Notifier == nil {
+		return
+	}
+	go func() {
+		if err := m.cfg.Notifier.SendMessage(m.cfg.ChatID, msg); err != nil {
+			log.Printf("Spread alert failed: %v", err)
+		}
+	}()
+}
+
+// ====== UTILITIES ======
+
+// Normalize maps "BTC-USDT" (BingX) and "BTCUSDT" (Binance) to one key.
+func Normalize(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "-", ""))
+}
@@ -0,0 +1,285 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// ====== POLICIES ======
+
+// OverflowPolicy decides what happens when a consumer queue is full.
+type OverflowPolicy int
+
+const (
+	DropOldest     OverflowPolicy = iota // discard the oldest queued event
+	CoalesceLatest                       // keep the latest price or ticker per stream and symbol; block for the rest
+	Block                                // wait for room, stalling the publisher
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case CoalesceLatest:
+		return "coalesce-latest"
+	case Block:
+		return "block"
+	}
+	return "unknown"
+}
+
+// ConsumerStats are the counters exported for a consumer.
+type ConsumerStats struct {
+	Policy    OverflowPolicy
+	Queued    int
+	Delivered uint64
+	Dropped   uint64
+	Coalesced uint64
+	LastLag   time.Duration // time the last delivered event spent queued
+	MaxLag    time.Duration
+}
+
+// ====== MAIN STRUCT ======
+
+// Dispatcher decouples the read loop from handlers: every consumer gets a
+// bounded queue drained by its own goroutine, so a slow handler (e.g. one
+// sending Telegram messages) never stalls reads.
+type Dispatcher struct {
+	consumers map[string]*consumer
+	mu        sync.RWMutex
+}
+
+type entry struct {
+	ev  Event
+	at  time.Time
+	key string
+}
+
+type consumer struct {
+	handlers Handlers
+	policy   OverflowPolicy
+	capacity int
+	queue    []*entry
+	latest   map[string]*entry
+	stats    ConsumerStats
+	closed   bool
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	done     chan struct{}
+}
+
+// ====== CONSTRUCTOR ======
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{consumers: make(map[string]*consumer)}
+}
+
+// Handlers returns handlers that publish into the dispatcher; pass them to
+// NewBingXWebSocketWithHandlers or NewPool.
+func (d *Dispatcher) Handlers() Handlers {
+	return Handlers{OnEvent: d.Publish}
+}
+
+// Subscribe registers a consumer with its own queue of the given capacity.
+// Subscribing an existing name replaces that consumer.
+func (d *Dispatcher) Subscribe(name string, handlers Handlers, capacity int, policy OverflowPolicy) {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+
+	c := &consumer{
+		handlers: handlers,
+		policy:   policy,
+		capacity: capacity,
+		latest:   make(map[string]*entry),
+		stats:    ConsumerStats{Policy: policy},
+		done:     make(chan struct{}),
+	}
+	c.notEmpty = sync.NewCond(&c.mu)
+	c.notFull = sync.NewCond(&c.mu)
+
+	d.mu.Lock()
+	old := d.consumers[name]
+	d.consumers[name] = c
+	d.mu.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+	go c.run()
+}
+
+// Unsubscribe stops and removes a consumer, discarding its queue.
+func (d *Dispatcher) Unsubscribe(name string) {
+	d.mu.Lock()
+	c := d.consumers[name]
+	delete(d.consumers, name)
+	d.mu.Unlock()
+
+	if c != nil {
+		c.close()
+	}
+}
+
+// ====== PUBLISH ======
+
+// Publish enqueues ev for every consumer according to its policy.
+func (d *Dispatcher) Publish(ev Event) {
+	d.mu.RLock()
+	consumers := make([]*consumer, 0, len(d.consumers))
+	for _, c := range d.consumers {
+		consumers = append(consumers, c)
+	}
+	d.mu.RUnlock()
+
+	now := time.Now()
+	for _, c := range consumers {
+		c.push(ev, now)
+	}
+}
+
+func (c *consumer) push(ev Event, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	key := ""
+	block := c.policy == Block
+	if c.policy == CoalesceLatest {
+		key = coalesceKey(ev)
+		if key == "" {
+			// Depth increments and trades are not states, so none may
+			// be lost.
+			block = true
+		} else if e, ok := c.latest[key]; ok {
+			e.ev = ev
+			c.stats.Coalesced++
+			return
+		}
+	}
+
+	for len(c.queue) >= c.capacity {
+		if block {
+			c.notFull.Wait()
+			if c.closed {
+				return
+			}
+			continue
+		}
+		c.dropOldest()
+	}
+
+	e := &entry{ev: ev, at: now, key: key}
+	c.queue = append(c.queue, e)
+	if key != "" {
+		c.latest[key] = e
+	}
+	c.notEmpty.Signal()
+}
+
+func (c *consumer) dropOldest() {
+	e := c.queue[0]
+	c.queue[0] = nil
+	c.queue = c.queue[1:]
+	if e.key != "" && c.latest[e.key] == e {
+		delete(c.latest, e.key)
+	}
+	c.stats.Dropped++
+}
+
+// ====== CONSUME ======
+
+func (c *consumer) run() {
+	defer close(c.done)
+
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closed {
+			c.notEmpty.Wait()
+		}
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+
+		e := c.queue[0]
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+		if e.key != "" && c.latest[e.key] == e {
+			delete(c.latest, e.key)
+		}
+		c.notFull.Signal()
+		c.mu.Unlock()
+
+		lag := time.Since(e.at)
+		c.handlers.Handle(e.ev)
+
+		c.mu.Lock()
+		c.stats.Delivered++
+		c.stats.LastLag = lag
+		if lag > c.stats.MaxLag {
+			c.stats.MaxLag = lag
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (c *consumer) close() {
+	c.mu.Lock()
+	c.closed = true
+	c.queue = nil
+	c.notEmpty.Broadcast()
+	c.notFull.Broadcast()
+	c.mu.Unlock()
+}
+
+// ====== STATS & CLOSE ======
+
+// Stats returns a snapshot of the counters of every consumer.
+func (d *Dispatcher) Stats() map[string]ConsumerStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	out := make(map[string]ConsumerStats, len(d.consumers))
+	for name, c := range d.consumers {
+		c.mu.Lock()
+		s := c.stats
+		s.Queued = len(c.queue)
+		c.mu.Unlock()
+		out[name] = s
+	}
+	return out
+}
+
+// Close stops all consumers and waits for in-flight handlers to return.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	consumers := d.consumers
+	d.consumers = make(map[string]*consumer)
+	d.mu.Unlock()
+
+	for _, c := range consumers {
+		c.close()
+	}
+	for _, c := range consumers {
+		<-c.done
+	}
+}
+
+// coalesceKey identifies the stream of an event that carries a full state,
+// so only the latest one per stream and symbol is kept. Events that are
+// changes or records (depth, trades, klines) return "" and are never
+// coalesced.
+func coalesceKey(ev Event) string {
+	switch ev.Kind {
+	case StreamPrice:
+		return string(ev.Kind) + "|" + ev.Symbol + "|" + ev.Price.Stream
+	case StreamTicker, StreamBookTicker:
+		return string(ev.Kind) + "|" + ev.Symbol
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package websocket
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedConsumer records what it receives; each handler call waits on gate
+// after announcing itself on started.
+type gatedConsumer struct {
+	mu      sync.Mutex
+	got     []string
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedConsumer() *gatedConsumer {
+	return &gatedConsumer{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (g *gatedConsumer) handlers() Handlers {
+	return Handlers{OnEvent: func(ev Event) {
+		g.started <- struct{}{}
+		<-g.gate
+		g.mu.Lock()
+		g.got = append(g.got, label(ev))
+		g.mu.Unlock()
+	}}
+}
+
+func (g *gatedConsumer) received(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		g.mu.Lock()
+		got := append([]string(nil), g.got...)
+		g.mu.Unlock()
+		if len(got) >= n || time.Now().After(deadline) {
+			return got
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func label(ev Event) string {
+	switch ev.Kind {
+	case StreamPrice:
+		return fmt.Sprintf("%s@%s=%g", ev.Symbol, ev.Price.Stream, ev.Price.Price)
+	case StreamDepth:
+		return fmt.Sprintf("%s@depth#%d", ev.Symbol, ev.Depth.LastUpdateID)
+	case StreamTrade:
+		return fmt.Sprintf("%s@trade=%g", ev.Symbol, ev.Trade.Price)
+	}
+	return string(ev.Kind)
+}
+
+func markPrice(symbol string, p float64) Event {
+	return Event{Kind: StreamPrice, Symbol: symbol, Price: PriceUpdate{Stream: "markPrice", Symbol: symbol, Price: p}}
+}
+
+func depthDelta(symbol string, id int64) Event {
+	return Event{Kind: StreamDepth, Symbol: symbol, Depth: &DepthUpdate{Symbol: symbol, LastUpdateID: id}}
+}
+
+func trade(symbol string, p float64) Event {
+	return Event{Kind: StreamTrade, Symbol: symbol, Trade: &Trade{Symbol: symbol, Price: p}}
+}
+
+// publishAsync publishes evs in order and closes the returned channel when
+// the last Publish returns.
+func publishAsync(d *Dispatcher, evs ...Event) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for _, ev := range evs {
+			d.Publish(ev)
+		}
+		close(done)
+	}()
+	return done
+}
+
+func returned(ch <-chan struct{}, within time.Duration) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(within):
+		return false
+	}
+}
+
+func TestDispatcherDropOldest(t *testing.T) {
+	d := NewDispatcher()
+	defer d.Close()
+	g := newGatedConsumer()
+	d.Subscribe("c", g.handlers(), 2, DropOldest)
+
+	d.Publish(markPrice("A", 1))
+	<-g.started // handler holds event 1; the queue is empty
+	for _, p := range []float64{2, 3, 4} {
+		d.Publish(markPrice("A", p))
+	}
+	close(g.gate)
+
+	want := []string{"A@markPrice=1", "A@markPrice=3", "A@markPrice=4"}
+	if got := g.received(t, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if s := d.Stats()["c"]; s.Dropped != 1 || s.Coalesced != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestDispatcherBlock(t *testing.T) {
+	d := NewDispatcher()
+	defer d.Close()
+	g := newGatedConsumer()
+	d.Subscribe("c", g.handlers(), 1, Block)
+
+	d.Publish(markPrice("A", 1))
+	<-g.started
+	done := publishAsync(d, markPrice("A", 2), markPrice("A", 3))
+	if returned(done, 20*time.Millisecond) {
+		t.Fatal("Publish did not block on a full queue")
+	}
+	close(g.gate)
+	if !returned(done, 2*time.Second) {
+		t.Fatal("Publish still blocked after the consumer caught up")
+	}
+
+	want := []string{"A@markPrice=1", "A@markPrice=2", "A@markPrice=3"}
+	if got := g.received(t, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if s := d.Stats()["c"]; s.Dropped != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestDispatcherCoalesceLatest(t *testing.T) {
+	d := NewDispatcher()
+	defer d.Close()
+	g := newGatedConsumer()
+	d.Subscribe("c", g.handlers(), 3, CoalesceLatest)
+
+	d.Publish(markPrice("A", 1))
+	<-g.started
+
+	// Prices for the same stream collapse to the latest.
+	d.Publish(markPrice("A", 2))
+	d.Publish(markPrice("A", 3))
+	d.Publish(markPrice("B", 1))
+
+	// Depth and trades are never coalesced or dropped: once the queue is
+	// full they wait for room.
+	done := publishAsync(d, depthDelta("A", 10), depthDelta("A", 11), trade("A", 5), trade("A", 5))
+	if returned(done, 20*time.Millisecond) {
+		t.Fatal("depth deltas were coalesced or dropped instead of waiting")
+	}
+	close(g.gate)
+	if !returned(done, 2*time.Second) {
+		t.Fatal("Publish still blocked after the consumer caught up")
+	}
+
+	want := []string{
+		"A@markPrice=1", "A@markPrice=3", "B@markPrice=1",
+		"A@depth#10", "A@depth#11", "A@trade=5", "A@trade=5",
+	}
+	if got := g.received(t, len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if s := d.Stats()["c"]; s.Coalesced != 1 || s.Dropped != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -0,0 +1,401 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ====== CONFIG ======
+
+type PoolConfig struct {
+	Connections    int           // number of shards; 0 sizes the pool from MaxPerConn
+	MaxPerConn     int           // subscription cap per connection
+	SubscribeDelay time.Duration // pause between subscriptions on one connection
+	Watchdog       *WatchdogConfig
+}
+
+const (
+	defaultMaxPerConn     = 200
+	defaultSubscribeDelay = 100 * time.Millisecond
+)
+
+// shardRetryBackoff is the first wait before retrying a shard that failed
+// to connect; it doubles up to a minute.
+var shardRetryBackoff = 2 * time.Second
+
+// ====== MAIN STRUCT ======
+
+// Pool shards subscriptions across several BingX connections and merges
+// their events into a single, serialized stream.
+type Pool struct {
+	cfg      PoolConfig
+	tokens   []string
+	handlers Handlers
+	shards   []*BingXWebSocket
+	ctx      context.Context
+	cancel   context.CancelFunc // stops shard retries on Close
+	path     string             // shard URL override for tests
+	mu       sync.Mutex
+	emitMu   sync.Mutex
+	shardMu  sync.Mutex     // serializes rebalance sends without holding mu
+	wg       sync.WaitGroup // shard retries
+}
+
+// ====== CONSTRUCTOR ======
+
+func NewPool(tokens []string, handlers Handlers, cfg PoolConfig) *Pool {
+	if cfg.MaxPerConn <= 0 {
+		cfg.MaxPerConn = defaultMaxPerConn
+	}
+	if cfg.SubscribeDelay <= 0 {
+		cfg.SubscribeDelay = defaultSubscribeDelay
+	}
+
+	p := &Pool{
+		cfg:      cfg,
+		tokens:   dedupe(tokens),
+		handlers: handlers,
+	}
+
+	n := cfg.Connections
+	if n <= 0 {
+		n = (len(p.tokens) + cfg.MaxPerConn - 1) / cfg.MaxPerConn
+	}
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		p.shards = append(p.shards, p.newShard())
+	}
+	return p
+}
+
+func (p *Pool) newShard() *BingXWebSocket {
+	shard := NewBingXWebSocketWithHandlers(nil, Handlers{})
+	shard.emit = p.emit
+	if p.path != "" {
+		shard.path = p.path
+	}
+	shard.subscribeDelay = p.cfg.SubscribeDelay
+	if p.cfg.Watchdog != nil {
+		shard.SetWatchdog(*p.cfg.Watchdog)
+	}
+	shard.onReconnect = func(err error) {
+		if err != nil {
+			log.Printf("Shard reconnect failed, moving its subscriptions: %v", err)
+		}
+		p.rebalance()
+	}
+	return shard
+}
+
+// ====== CONNECTION ======
+
+// Connect distributes the tokens and connects all shards concurrently.
+func (p *Pool) Connect() error {
+	return p.ConnectContext(context.Background())
+}
+
+// ConnectContext is Connect with shards bound to ctx.
+func (p *Pool) ConnectContext(ctx context.Context) error {
+	p.mu.Lock()
+	if len(p.tokens) > len(p.shards)*p.cfg.MaxPerConn {
+		p.mu.Unlock()
+		return fmt.Errorf("%d subscriptions exceed pool capacity %d", len(p.tokens), len(p.shards)*p.cfg.MaxPerConn)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p.ctx, p.cancel = ctx, cancel
+	for i, tokens := range chunk(p.tokens, len(p.shards)) {
+		_ = p.shards[i].setTokens(tokens)
+	}
+	total := len(p.tokens)
+	shards := append([]*BingXWebSocket(nil), p.shards...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(shards))
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard *BingXWebSocket) {
+			defer wg.Done()
+			errs[i] = shard.ConnectContext(ctx)
+		}(i, shard)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Shard %d/%d failed: %v", i+1, len(shards), err)
+			failed++
+		}
+	}
+	if failed == len(shards) {
+		cancel()
+		p.mu.Lock()
+		p.ctx, p.cancel = nil, nil
+		p.mu.Unlock()
+		return fmt.Errorf("all %d WebSocket shards failed to connect", len(shards))
+	}
+	if failed > 0 {
+		p.rebalance()
+		for i, err := range errs {
+			if err != nil {
+				p.retry(ctx, shards[i])
+			}
+		}
+	}
+
+	log.Printf("✅ WebSocket pool connected: %d subscriptions over %d connections", total, len(shards)-failed)
+	return nil
+}
+
+// Subscribe adds data types to the pool, growing it when every shard is full
+// and the connection count was not fixed. Before Connect the tokens are
+// only recorded. If they cannot be placed the pool keeps its previous set.
+func (p *Pool) Subscribe(tokens ...string) error {
+	p.mu.Lock()
+	prev := p.tokens
+	merged := dedupe(append(append([]string(nil), p.tokens...), tokens...))
+	if p.cfg.Connections > 0 && len(merged) > len(p.shards)*p.cfg.MaxPerConn {
+		p.mu.Unlock()
+		return fmt.Errorf("%d subscriptions exceed pool capacity %d", len(merged), len(p.shards)*p.cfg.MaxPerConn)
+	}
+	p.tokens = merged
+	var grown []*BingXWebSocket
+	if p.cfg.Connections <= 0 {
+		for len(p.tokens) > len(p.shards)*p.cfg.MaxPerConn {
+			shard := p.newShard()
+			p.shards = append(p.shards, shard)
+			grown = append(grown, shard)
+		}
+	}
+	ctx := p.ctx
+	p.mu.Unlock()
+
+	if ctx == nil {
+		return nil
+	}
+	for _, shard := range grown {
+		if err := shard.ConnectContext(ctx); err != nil {
+			log.Printf("New shard failed to connect: %v", err)
+			p.retry(ctx, shard)
+		}
+	}
+	if err := p.rebalance(); err != nil {
+		p.mu.Lock()
+		p.tokens = prev
+		p.mu.Unlock()
+		if rerr := p.rebalance(); rerr != nil {
+			log.Printf("Restoring subscriptions failed: %v", rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// retry runs retryShard on the pool's wait group, so Close waits for it.
+func (p *Pool) retry(ctx context.Context, shard *BingXWebSocket) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.retryShard(ctx, shard)
+	}()
+}
+
+// retryShard reconnects a shard whose first connection failed, with
+// exponential backoff, and hands it a share of the tokens once it is up.
+func (p *Pool) retryShard(ctx context.Context, shard *BingXWebSocket) {
+	backoff := shardRetryBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		err := shard.ConnectContext(ctx)
+		if err == nil {
+			log.Println("Shard connected after retry")
+			if err := p.rebalance(); err != nil {
+				log.Printf("Rebalance failed: %v", err)
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Shard retry failed: %v", err)
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// Unsubscribe removes data types from the pool.
+func (p *Pool) Unsubscribe(tokens ...string) error {
+	drop := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		drop[t] = true
+	}
+
+	p.mu.Lock()
+	kept := p.tokens[:0]
+	for _, t := range p.tokens {
+		if !drop[t] {
+			kept = append(kept, t)
+		}
+	}
+	p.tokens = kept
+	p.mu.Unlock()
+
+	return p.rebalance()
+}
+
+// Run connects and blocks until ctx is cancelled, then closes every shard.
+func (p *Pool) Run(ctx context.Context) error {
+	if err := p.ConnectContext(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	if err := p.Close(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Close closes every shard and waits for their goroutines and shard
+// retries to exit.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.ctx, p.cancel = nil, nil
+	shards := append([]*BingXWebSocket(nil), p.shards...)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	var firstErr error
+	for _, shard := range shards {
+		if err := shard.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ====== SHARDING ======
+
+// rebalance spreads tokens evenly over the connected shards. Tokens keep
+// their shard where possible; tokens of disconnected or overloaded shards
+// move to the least loaded healthy one. The plan is made under mu and
+// sent without it, so slow subscriptions don't block Subscribe.
+func (p *Pool) rebalance() error {
+	p.shardMu.Lock()
+	defer p.shardMu.Unlock()
+
+	p.mu.Lock()
+	shards, assigned, err := p.plan()
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for i, shard := range shards {
+		if err := shard.setTokens(assigned[i]); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("shard %d: %w", i+1, err)
+		}
+	}
+	return firstErr
+}
+
+// plan assigns p.tokens to shards; mu must be held.
+func (p *Pool) plan() ([]*BingXWebSocket, map[int][]string, error) {
+	var healthy []int
+	for i, shard := range p.shards {
+		if shard.connected() {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, nil, fmt.Errorf("no connected WebSocket shards")
+	}
+
+	target := (len(p.tokens) + len(healthy) - 1) / len(healthy)
+	if target > p.cfg.MaxPerConn {
+		return nil, nil, fmt.Errorf("%d subscriptions exceed pool capacity %d", len(p.tokens), len(healthy)*p.cfg.MaxPerConn)
+	}
+
+	wanted := make(map[string]bool, len(p.tokens))
+	for _, t := range p.tokens {
+		wanted[t] = true
+	}
+
+	assigned := make(map[int][]string, len(p.shards))
+	placed := make(map[string]bool, len(p.tokens))
+	for _, i := range healthy {
+		for _, t := range p.shards[i].Tokens() {
+			if wanted[t] && !placed[t] && len(assigned[i]) < target {
+				assigned[i] = append(assigned[i], t)
+				placed[t] = true
+			}
+		}
+	}
+
+	for _, t := range p.tokens {
+		if placed[t] {
+			continue
+		}
+		sort.SliceStable(healthy, func(a, b int) bool {
+			return len(assigned[healthy[a]]) < len(assigned[healthy[b]])
+		})
+		assigned[healthy[0]] = append(assigned[healthy[0]], t)
+		placed[t] = true
+	}
+	return append([]*BingXWebSocket(nil), p.shards...), assigned, nil
+}
+
+// emit serializes events from all shards into the pool handlers.
+func (p *Pool) emit(ev Event) {
+	p.emitMu.Lock()
+	defer p.emitMu.Unlock()
+	p.handlers.Handle(ev)
+}
+
+// ====== UTILITIES ======
+
+// chunk splits tokens into n nearly equal contiguous groups.
+func chunk(tokens []string, n int) [][]string {
+	out := make([][]string, n)
+	size := (len(tokens) + n - 1) / n
+	for i := 0; i < n; i++ {
+		lo, hi := i*size, (i+1)*size
+		if lo > len(tokens) {
+			lo = len(tokens)
+		}
+		if hi > len(tokens) {
+			hi = len(tokens)
+		}
+		out[i] = append([]string(nil), tokens[lo:hi]...)
+	}
+	return out
+}
+
+func dedupe(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
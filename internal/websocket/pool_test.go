@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// testServer accepts WebSocket connections and discards what they send.
+// reject, if set, refuses the nth dial (counting from 1).
+type testServer struct {
+	*httptest.Server
+	dials  atomic.Int32
+	reject func(n int32) bool
+}
+
+func newTestServer(t *testing.T, reject func(n int32) bool) *testServer {
+	t.Helper()
+	s := &testServer{reject: reject}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.dials.Add(1)
+		if s.reject != nil && s.reject(n) {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestPool(t *testing.T, s *testServer, tokens []string, cfg PoolConfig) *Pool {
+	t.Helper()
+	cfg.SubscribeDelay = time.Microsecond
+	p := NewPool(tokens, Handlers{}, cfg)
+	p.path = "ws" + strings.TrimPrefix(s.URL, "http")
+	for _, shard := range p.shards {
+		shard.path = p.path
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func setRetryBackoff(t *testing.T, d time.Duration) {
+	old := shardRetryBackoff
+	shardRetryBackoff = d
+	t.Cleanup(func() { shardRetryBackoff = old })
+}
+
+// checkSpread fails unless every token sits on exactly one shard, with at
+// most max per shard.
+func checkSpread(t *testing.T, p *Pool, want []string, max int) {
+	t.Helper()
+	p.mu.Lock()
+	shards := append([]*BingXWebSocket(nil), p.shards...)
+	p.mu.Unlock()
+
+	var all []string
+	for i, shard := range shards {
+		tokens := shard.Tokens()
+		if len(tokens) > max {
+			t.Errorf("shard %d holds %d tokens, max %d", i, len(tokens), max)
+		}
+		all = append(all, tokens...)
+	}
+	sort.Strings(all)
+	want = append([]string(nil), want...)
+	sort.Strings(want)
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("shards hold %v, want %v", all, want)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPoolShardsAndGrows(t *testing.T) {
+	s := newTestServer(t, nil)
+	tokens := []string{"A@trade", "B@trade", "C@trade", "D@trade", "E@trade"}
+	p := newTestPool(t, s, tokens, PoolConfig{MaxPerConn: 2})
+
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.shards) != 3 {
+		t.Fatalf("%d shards, want 3", len(p.shards))
+	}
+	checkSpread(t, p, tokens, 2)
+
+	more := []string{"F@trade", "G@trade"}
+	if err := p.Subscribe(more...); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.shards) != 4 || s.dials.Load() != 4 {
+		t.Fatalf("%d shards after %d dials, want 4", len(p.shards), s.dials.Load())
+	}
+	checkSpread(t, p, append(tokens, more...), 2)
+}
+
+func TestPoolRebalancesAroundFailedShard(t *testing.T) {
+	setRetryBackoff(t, 200*time.Millisecond)
+	s := newTestServer(t, func(n int32) bool { return n == 2 })
+	tokens := []string{"A@trade", "B@trade", "C@trade", "D@trade"}
+	p := newTestPool(t, s, tokens, PoolConfig{Connections: 2, MaxPerConn: 4})
+
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	// The healthy shard takes everything until the other one is back.
+	checkSpread(t, p, tokens, 4)
+	for _, shard := range p.shards {
+		if !shard.connected() && len(shard.Tokens()) > 0 {
+			t.Errorf("failed shard kept %v", shard.Tokens())
+		}
+	}
+
+	waitFor(t, "retried shard", func() bool {
+		return p.shards[0].connected() && p.shards[1].connected() &&
+			len(p.shards[0].Tokens()) == 2 && len(p.shards[1].Tokens()) == 2
+	})
+	checkSpread(t, p, tokens, 2)
+}
+
+func TestPoolSubscribeBeforeConnect(t *testing.T) {
+	s := newTestServer(t, nil)
+	p := newTestPool(t, s, nil, PoolConfig{MaxPerConn: 2})
+
+	tokens := []string{"A@trade", "B@trade", "C@trade"}
+	if err := p.Subscribe(tokens...); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.dials.Load(); n != 0 {
+		t.Fatalf("Subscribe before Connect dialled %d times", n)
+	}
+
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.dials.Load(); n != 2 {
+		t.Fatalf("%d dials, want 2", n)
+	}
+	checkSpread(t, p, tokens, 2)
+}
+
+func TestPoolSubscribeRollsBack(t *testing.T) {
+	s := newTestServer(t, nil)
+	p := newTestPool(t, s, []string{"A@trade"}, PoolConfig{Connections: 1, MaxPerConn: 2})
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	tokens := func() []string {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return append([]string(nil), p.tokens...)
+	}
+
+	if err := p.Subscribe("B@trade", "C@trade"); err == nil {
+		t.Fatal("over-capacity Subscribe succeeded")
+	}
+	if got := tokens(); !reflect.DeepEqual(got, []string{"A@trade"}) {
+		t.Fatalf("tokens after over-capacity Subscribe = %v", got)
+	}
+
+	// With no connected shard the new token cannot be placed.
+	p.shards[0].dropConn()
+	if err := p.Subscribe("B@trade"); err == nil {
+		t.Fatal("Subscribe without connected shards succeeded")
+	}
+	if got := tokens(); !reflect.DeepEqual(got, []string{"A@trade"}) {
+		t.Fatalf("tokens after failed rebalance = %v", got)
+	}
+}
+
+func TestPoolCloseStopsRetries(t *testing.T) {
+	setRetryBackoff(t, 5*time.Millisecond)
+	s := newTestServer(t, func(n int32) bool { return n > 1 })
+	p := newTestPool(t, s, []string{"A@trade", "B@trade"}, PoolConfig{Connections: 2})
+
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a retry", func() bool { return s.dials.Load() > 2 })
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := s.dials.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := s.dials.Load(); got != n {
+		t.Errorf("%d dials after Close", got-n)
+	}
+}
@@ -0,0 +1,241 @@
+package websocket
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ====== FRAME FORMAT ======
+
+// FrameKind tells whether a recorded frame is the raw gzip payload received
+// from BingX or the same frame after decompress.
+type FrameKind byte
+
+const (
+	FrameRaw     FrameKind = 0
+	FrameDecoded FrameKind = 1
+)
+
+// Frame is a single recorded message. On disk (inside a gzip stream) each
+// frame is: int64 unix-nano timestamp, 1-byte kind, uint32 length, payload.
+type Frame struct {
+	Time time.Time
+	Kind FrameKind
+	Data []byte
+}
+
+// ====== RECORDER ======
+
+// Recorder writes frames to a gzip-compressed session file. Buffered
+// frames are flushed to the file every recorderFlushInterval, so a crash
+// loses at most that much of the session.
+type Recorder struct {
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// recorderFlushInterval is how often a Recorder flushes to disk.
+var recorderFlushInterval = time.Second
+
+// NewRecorder creates (or truncates) a session file at path.
+//
+// Example: rec, _ := websocket.NewRecorder("./sessions/2025-10-31.bxrec.gz")
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create recording %s: %w", path, err)
+	}
+	gz := gzip.NewWriter(f)
+	r := &Recorder{
+		file: f,
+		gz:   gz,
+		buf:  bufio.NewWriter(gz),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go r.flushLoop()
+	return r, nil
+}
+
+func (r *Recorder) flushLoop() {
+	defer close(r.done)
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				log.Printf("Recorder flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Flush writes buffered frames through to the file. The session stays
+// readable up to the last flush even if the process dies.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.buf.Flush(); err != nil {
+		return fmt.Errorf("flush recording: %w", err)
+	}
+	if err := r.gz.Flush(); err != nil {
+		return fmt.Errorf("flush gzip: %w", err)
+	}
+	return nil
+}
+
+// SetRecorder records every frame handled by the client. Pass nil to stop.
+func (ws *BingXWebSocket) SetRecorder(r *Recorder) {
+	ws.mu.Lock()
+	ws.recorder = r
+	ws.mu.Unlock()
+}
+
+// Record appends a frame.
+func (r *Recorder) Record(kind FrameKind, at time.Time, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var header [13]byte
+	binary.BigEndian.PutUint64(header[0:8], uint64(at.UnixNano()))
+	header[8] = byte(kind)
+	binary.BigEndian.PutUint32(header[9:13], uint32(len(data)))
+
+	if _, err := r.buf.Write(header[:]); err != nil {
+		return fmt.Errorf("write frame header: %w", err)
+	}
+	if _, err := r.buf.Write(data); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the session file.
+func (r *Recorder) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.buf.Flush(); err != nil {
+		return fmt.Errorf("flush recording: %w", err)
+	}
+	if err := r.gz.Close(); err != nil {
+		return fmt.Errorf("close gzip: %w", err)
+	}
+	return r.file.Close()
+}
+
+// ====== READER ======
+
+// FrameReader reads frames back from a session file.
+type FrameReader struct {
+	file *os.File
+	gz   *gzip.Reader
+	buf  *bufio.Reader
+}
+
+func OpenRecording(path string) (*FrameReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open recording %s: %w", path, err)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("open gzip %s: %w", path, err)
+	}
+	return &FrameReader{file: f, gz: gz, buf: bufio.NewReader(gz)}, nil
+}
+
+// Next returns the next frame, or io.EOF at the end of the session.
+func (fr *FrameReader) Next() (Frame, error) {
+	var header [13]byte
+	if _, err := io.ReadFull(fr.buf, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return Frame{}, fmt.Errorf("truncated frame header: %w", err)
+		}
+		return Frame{}, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(header[9:13]))
+	if _, err := io.ReadFull(fr.buf, data); err != nil {
+		return Frame{}, fmt.Errorf("truncated frame: %w", err)
+	}
+
+	return Frame{
+		Time: time.Unix(0, int64(binary.BigEndian.Uint64(header[0:8]))),
+		Kind: FrameKind(header[8]),
+		Data: data,
+	}, nil
+}
+
+func (fr *FrameReader) Close() error {
+	fr.gz.Close()
+	return fr.file.Close()
+}
+
+// ====== REPLAY ======
+
+// Replay feeds the raw frames of a session file through handleMessage, as
+// if they had just been received, until the file ends or ctx is cancelled.
+// speed scales the original pacing: 1 is real time, 10 is ten times
+// faster, 0 replays without delays. Decoded frames are skipped since they
+// are reproduced by decompress.
+func (ws *BingXWebSocket) Replay(ctx context.Context, path string, speed float64) error {
+	fr, err := OpenRecording(path)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	var prev time.Time
+	for {
+		frame, err := fr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if frame.Kind != FrameRaw {
+			continue
+		}
+
+		if speed > 0 && !prev.IsZero() {
+			if gap := frame.Time.Sub(prev); gap > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(float64(gap) / speed)):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		prev = frame.Time
+
+		ws.handleMessage(frame.Data)
+	}
+}
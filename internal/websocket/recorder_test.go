@@ -0,0 +1,138 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func priceMsg(p float64) string {
+	return fmt.Sprintf(`{"code":0,"dataType":"BTC-USDT@markPrice","data":{"e":"markPriceUpdate","E":1730366400000,"s":"BTC-USDT","p":"%g"}}`, p)
+}
+
+// recordSession writes raw frames for prices, one gap apart, each followed
+// by its decoded form.
+func recordSession(t *testing.T, path string, gap time.Duration, prices ...float64) []Frame {
+	t.Helper()
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []Frame
+	at := time.Unix(1730366400, 0)
+	for _, p := range prices {
+		msg := priceMsg(p)
+		frames = append(frames,
+			Frame{Time: at, Kind: FrameRaw, Data: gzipFrame(t, msg)},
+			Frame{Time: at, Kind: FrameDecoded, Data: []byte(msg)})
+		at = at.Add(gap)
+	}
+	for _, f := range frames {
+		if err := rec.Record(f.Kind, f.Time, f.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return frames
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.bxrec.gz")
+	want := recordSession(t, path, time.Millisecond, 72015.35, 72016.5)
+
+	fr, err := OpenRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Frame
+	for {
+		f, err := fr.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, f)
+	}
+	fr.Close()
+	if len(got) != len(want) {
+		t.Fatalf("read %d frames, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].Kind != want[i].Kind || string(got[i].Data) != string(want[i].Data) {
+			t.Errorf("frame %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var prices []float64
+	ws := NewBingXWebSocket(nil, func(u PriceUpdate) { prices = append(prices, u.Price) })
+	if err := ws.Replay(context.Background(), path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prices, []float64{72015.35, 72016.5}) {
+		t.Errorf("replayed prices = %v", prices)
+	}
+}
+
+func TestRecorderFlushesWhileOpen(t *testing.T) {
+	old := recorderFlushInterval
+	recorderFlushInterval = 5 * time.Millisecond
+	t.Cleanup(func() { recorderFlushInterval = old })
+
+	path := filepath.Join(t.TempDir(), "session.bxrec.gz")
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Close()
+	if err := rec.Record(FrameDecoded, time.Now(), []byte(priceMsg(1))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without Close, the frame must reach the file on the flush timer.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fr, err := OpenRecording(path)
+		if err == nil {
+			f, err := fr.Next()
+			fr.Close()
+			if err == nil && string(f.Data) == priceMsg(1) {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("frame never flushed to disk")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReplayStopsOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.bxrec.gz")
+	recordSession(t, path, time.Hour, 1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var prices []float64
+	ws := NewBingXWebSocket(nil, func(u PriceUpdate) {
+		prices = append(prices, u.Price)
+		cancel() // the next frame is an hour away at real-time speed
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- ws.Replay(ctx, path, 1) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Replay = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Replay ignored cancellation")
+	}
+	if !reflect.DeepEqual(prices, []float64{1}) {
+		t.Errorf("replayed prices = %v", prices)
+	}
+}
@@ -0,0 +1,552 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ====== STREAM KINDS ======
+
+// StreamKind identifies the type of a BingX market data stream.
+type StreamKind string
+
+const (
+	StreamPrice      StreamKind = "price"
+	StreamDepth      StreamKind = "depth"
+	StreamTrade      StreamKind = "trade"
+	StreamKline      StreamKind = "kline"
+	StreamTicker     StreamKind = "ticker"
+	StreamBookTicker StreamKind = "bookTicker"
+)
+
+// ====== EVENT TYPES ======
+
+type Level struct {
+	Price    float64
+	Quantity float64
+}
+
+type DepthUpdate struct {
+	Symbol       string
+	Action       string // "all" for full snapshots, "update" for increments
+	LastUpdateID int64
+	Bids         []Level
+	Asks         []Level
+	Time         int64
+}
+
+type Trade struct {
+	Symbol     string
+	Price      float64
+	Quantity   float64
+	BuyerMaker bool
+	Time       int64
+}
+
+type Kline struct {
+	Symbol   string
+	Interval string
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+	Time     int64
+}
+
+type Ticker struct {
+	Symbol        string
+	Last          float64
+	Open          float64
+	High          float64
+	Low           float64
+	Change        float64
+	ChangePercent float64
+	Volume        float64
+	QuoteVolume   float64
+	BidPrice      float64
+	AskPrice      float64
+	Time          int64
+}
+
+type BookTicker struct {
+	Symbol   string
+	BidPrice float64
+	BidQty   float64
+	AskPrice float64
+	AskQty   float64
+	Time     int64
+}
+
+// Event is a decoded market data message. Exactly one payload field is set,
+// matching Kind. Price is stored inline so the price hot path does not
+// allocate.
+type Event struct {
+	Kind       StreamKind
+	Symbol     string
+	Price      PriceUpdate
+	Depth      *DepthUpdate
+	Trade      *Trade
+	Kline      *Kline
+	Ticker     *Ticker
+	BookTicker *BookTicker
+}
+
+// ====== HANDLERS ======
+
+// Handlers holds per-stream callbacks. Nil callbacks are skipped. OnEvent
+// receives every event before the typed callback.
+type Handlers struct {
+	OnEvent      func(Event)
+	OnPrice      func(PriceUpdate)
+	OnDepth      func(DepthUpdate)
+	OnTrade      func(Trade)
+	OnKline      func(Kline)
+	OnTicker     func(Ticker)
+	OnBookTicker func(BookTicker)
+}
+
+// Handle routes an event to the matching callback.
+func (h Handlers) Handle(ev Event) {
+	if h.OnEvent != nil {
+		h.OnEvent(ev)
+	}
+
+	switch ev.Kind {
+	case StreamPrice:
+		if h.OnPrice != nil {
+			h.OnPrice(ev.Price)
+		}
+	case StreamDepth:
+		if h.OnDepth != nil && ev.Depth != nil {
+			h.OnDepth(*ev.Depth)
+		}
+	case StreamTrade:
+		if h.OnTrade != nil && ev.Trade != nil {
+			h.OnTrade(*ev.Trade)
+		}
+	case StreamKline:
+		if h.OnKline != nil && ev.Kline != nil {
+			h.OnKline(*ev.Kline)
+		}
+	case StreamTicker:
+		if h.OnTicker != nil && ev.Ticker != nil {
+			h.OnTicker(*ev.Ticker)
+		}
+	case StreamBookTicker:
+		if h.OnBookTicker != nil && ev.BookTicker != nil {
+			h.OnBookTicker(*ev.BookTicker)
+		}
+	}
+}
+
+// ====== ROUTING ======
+
+// streamKind maps a dataType such as "BTC-USDT@depth20@500ms" or
+// "BTC-USDT@kline_1m" to its stream kind and parameter (depth level or
+// kline interval).
+func streamKind(dataType string) (StreamKind, string, bool) {
+	idx := strings.IndexRune(dataType, '@')
+	if idx < 0 {
+		return "", "", false
+	}
+	stream := dataType[idx+1:]
+	if end := strings.IndexRune(stream, '@'); end >= 0 {
+		stream = stream[:end]
+	}
+
+	switch {
+	case stream == "lastPrice", stream == "markPrice":
+		return StreamPrice, stream, true
+	case stream == "trade":
+		return StreamTrade, "", true
+	case stream == "ticker":
+		return StreamTicker, "", true
+	case stream == "bookTicker":
+		return StreamBookTicker, "", true
+	case stream == "incrDepth":
+		return StreamDepth, "", true
+	case strings.HasPrefix(stream, "depth"):
+		return StreamDepth, strings.TrimPrefix(stream, "depth"), true
+	case strings.HasPrefix(stream, "kline_"):
+		return StreamKline, strings.TrimPrefix(stream, "kline_"), true
+	}
+	return "", "", false
+}
+
+// decodeEvents decodes the data payload of a market message into events.
+// Trade messages may carry several trades and yield one event per trade.
+func decodeEvents(m MarketData) ([]Event, error) {
+	kind, param, ok := streamKind(m.DataType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported dataType %s", m.DataType)
+	}
+	symbol := parseSymbol(m.DataType)
+
+	switch kind {
+	case StreamPrice:
+		p, err := decodePrice(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		return []Event{{Kind: kind, Symbol: symbol, Price: PriceUpdate{
+			Type:   "priceUpdate",
+			Stream: param,
+			Symbol: symbol,
+			Price:  p,
+		}}}, nil
+	case StreamDepth:
+		d, err := decodeDepth(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		d.Symbol = symbol
+		return []Event{{Kind: kind, Symbol: symbol, Depth: d}}, nil
+	case StreamTrade:
+		trades, err := decodeTrades(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		events := make([]Event, len(trades))
+		for i := range trades {
+			trades[i].Symbol = symbol
+			events[i] = Event{Kind: kind, Symbol: symbol, Trade: &trades[i]}
+		}
+		return events, nil
+	case StreamKline:
+		k, err := decodeKline(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		k.Symbol, k.Interval = symbol, param
+		return []Event{{Kind: kind, Symbol: symbol, Kline: k}}, nil
+	case StreamTicker:
+		t, err := decodeTicker(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		t.Symbol = symbol
+		return []Event{{Kind: kind, Symbol: symbol, Ticker: t}}, nil
+	case StreamBookTicker:
+		b, err := decodeBookTicker(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		b.Symbol = symbol
+		return []Event{{Kind: kind, Symbol: symbol, BookTicker: b}}, nil
+	}
+	return nil, nil
+}
+
+// ====== FAST PATH ======
+
+var (
+	dataTypeKey  = []byte(`"dataType":"`)
+	dataKey      = []byte(`"data":{`)
+	markPriceKey = []byte(`"p":"`)
+	lastPriceKey = []byte(`"c":"`)
+)
+
+// decodeFastPrice extracts symbol, stream and price from markPrice/lastPrice
+// messages by scanning the bytes directly. ok is false for any other
+// message, and the caller falls back to encoding/json.
+func decodeFastPrice(data []byte) (symbol, stream string, price float64, ok bool) {
+	dataType, ok := stringValue(data, dataTypeKey)
+	if !ok {
+		return "", "", 0, false
+	}
+
+	name := dataType[len(parseSymbol(dataType)):]
+	if len(name) > 0 {
+		name = name[1:]
+	}
+	if end := bytes.IndexByte(name, '@'); end >= 0 {
+		name = name[:end]
+	}
+	switch string(name) {
+	case "markPrice":
+		stream = "markPrice"
+	case "lastPrice":
+		stream = "lastPrice"
+	default:
+		return "", "", 0, false
+	}
+
+	idx := bytes.Index(data, dataKey)
+	if idx < 0 {
+		return "", "", 0, false
+	}
+	payload := data[idx+len(dataKey):]
+
+	value, ok := stringValue(payload, markPriceKey)
+	if !ok {
+		if value, ok = stringValue(payload, lastPriceKey); !ok {
+			return "", "", 0, false
+		}
+	}
+
+	price, err := parsePrice(value)
+	if err != nil {
+		return "", "", 0, false
+	}
+	return internSymbol(parseSymbol(dataType)), stream, price, true
+}
+
+// stringValue returns the JSON string following key, without unescaping;
+// values containing escapes are rejected.
+func stringValue(data, key []byte) ([]byte, bool) {
+	idx := bytes.Index(data, key)
+	if idx < 0 {
+		return nil, false
+	}
+	rest := data[idx+len(key):]
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 || bytes.IndexByte(rest[:end], '\\') >= 0 {
+		return nil, false
+	}
+	return rest[:end], true
+}
+
+// symbols interns symbol strings so the fast path reuses one string per
+// symbol instead of allocating per message.
+var symbols = struct {
+	sync.RWMutex
+	m map[string]string
+}{m: make(map[string]string)}
+
+func internSymbol(b []byte) string {
+	symbols.RLock()
+	s, ok := symbols.m[string(b)]
+	symbols.RUnlock()
+	if ok {
+		return s
+	}
+
+	symbols.Lock()
+	defer symbols.Unlock()
+	if s, ok = symbols.m[string(b)]; !ok {
+		s = string(b)
+		symbols.m[s] = s
+	}
+	return s
+}
+
+// ====== DECODERS ======
+
+func decodePrice(raw json.RawMessage) (float64, error) {
+	var d struct {
+		MarkPrice string `json:"p"`
+		LastPrice string `json:"c"`
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return 0, fmt.Errorf("decode price: %w", err)
+	}
+	s := d.MarkPrice
+	if s == "" {
+		s = d.LastPrice
+	}
+	if s == "" {
+		return 0, fmt.Errorf("empty price")
+	}
+	return parsePrice(s)
+}
+
+func decodeDepth(raw json.RawMessage) (*DepthUpdate, error) {
+	var d struct {
+		Action       string     `json:"action"`
+		LastUpdateID int64      `json:"lastUpdateId"`
+		Time         int64      `json:"T"`
+		Bids         [][]string `json:"bids"`
+		Asks         [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("decode depth: %w", err)
+	}
+
+	bids, err := parseLevels(d.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseLevels(d.Asks)
+	if err != nil {
+		return nil, err
+	}
+	return &DepthUpdate{
+		Action:       d.Action,
+		LastUpdateID: d.LastUpdateID,
+		Bids:         bids,
+		Asks:         asks,
+		Time:         d.Time,
+	}, nil
+}
+
+func decodeTrades(raw json.RawMessage) ([]Trade, error) {
+	var d []struct {
+		Price      string `json:"p"`
+		Quantity   string `json:"q"`
+		Time       int64  `json:"T"`
+		BuyerMaker bool   `json:"m"`
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("decode trades: %w", err)
+	}
+
+	trades := make([]Trade, 0, len(d))
+	for _, t := range d {
+		price, err := parsePrice(t.Price)
+		if err != nil {
+			return nil, fmt.Errorf("trade price %s: %w", t.Price, err)
+		}
+		trades = append(trades, Trade{
+			Price:      price,
+			Quantity:   parseOptional(t.Quantity),
+			BuyerMaker: t.BuyerMaker,
+			Time:       t.Time,
+		})
+	}
+	return trades, nil
+}
+
+func decodeKline(raw json.RawMessage) (*Kline, error) {
+	type rawKline struct {
+		Open   string `json:"o"`
+		High   string `json:"h"`
+		Low    string `json:"l"`
+		Close  string `json:"c"`
+		Volume string `json:"v"`
+		Time   int64  `json:"T"`
+	}
+
+	// BingX sends klines as a one-element array; accept a bare object too.
+	var list []rawKline
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var single rawKline
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("decode kline: %w", err)
+		}
+		list = []rawKline{single}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("empty kline")
+	}
+
+	k := list[len(list)-1]
+	closePrice, err := parsePrice(k.Close)
+	if err != nil {
+		return nil, fmt.Errorf("kline close %s: %w", k.Close, err)
+	}
+	return &Kline{
+		Open:   parseOptional(k.Open),
+		High:   parseOptional(k.High),
+		Low:    parseOptional(k.Low),
+		Close:  closePrice,
+		Volume: parseOptional(k.Volume),
+		Time:   k.Time,
+	}, nil
+}
+
+func decodeTicker(raw json.RawMessage) (*Ticker, error) {
+	// encoding/json matches keys case-insensitively and the ticker uses
+	// pairs like "l"/"L" and "o"/"O", so every key is declared.
+	var d struct {
+		Event         string `json:"e"`
+		Time          int64  `json:"E"`
+		Last          string `json:"c"`
+		LastQty       string `json:"L"`
+		Open          string `json:"o"`
+		OpenTime      int64  `json:"O"`
+		CloseTime     int64  `json:"C"`
+		High          string `json:"h"`
+		Low           string `json:"l"`
+		Change        string `json:"p"`
+		ChangePercent string `json:"P"`
+		Volume        string `json:"v"`
+		QuoteVolume   string `json:"q"`
+		BidPrice      string `json:"B"`
+		BidQty        string `json:"b"`
+		AskPrice      string `json:"A"`
+		AskQty        string `json:"a"`
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("decode ticker: %w", err)
+	}
+
+	last, err := parsePrice(d.Last)
+	if err != nil {
+		return nil, fmt.Errorf("ticker price %s: %w", d.Last, err)
+	}
+	return &Ticker{
+		Last:          last,
+		Open:          parseOptional(d.Open),
+		High:          parseOptional(d.High),
+		Low:           parseOptional(d.Low),
+		Change:        parseOptional(d.Change),
+		ChangePercent: parseOptional(d.ChangePercent),
+		Volume:        parseOptional(d.Volume),
+		QuoteVolume:   parseOptional(d.QuoteVolume),
+		BidPrice:      parseOptional(d.BidPrice),
+		AskPrice:      parseOptional(d.AskPrice),
+		Time:          d.Time,
+	}, nil
+}
+
+func decodeBookTicker(raw json.RawMessage) (*BookTicker, error) {
+	var d struct {
+		Event    string `json:"e"`
+		Time     int64  `json:"E"`
+		BidPrice string `json:"b"`
+		BidQty   string `json:"B"`
+		AskPrice string `json:"a"`
+		AskQty   string `json:"A"`
+	}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("decode bookTicker: %w", err)
+	}
+
+	bid, err := parsePrice(d.BidPrice)
+	if err != nil {
+		return nil, fmt.Errorf("bid price %s: %w", d.BidPrice, err)
+	}
+	ask, err := parsePrice(d.AskPrice)
+	if err != nil {
+		return nil, fmt.Errorf("ask price %s: %w", d.AskPrice, err)
+	}
+	return &BookTicker{
+		BidPrice: bid,
+		BidQty:   parseOptional(d.BidQty),
+		AskPrice: ask,
+		AskQty:   parseOptional(d.AskQty),
+		Time:     d.Time,
+	}, nil
+}
+
+// parseLevels converts [["price","qty"], ...] pairs into levels.
+func parseLevels(raw [][]string) ([]Level, error) {
+	levels := make([]Level, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			continue
+		}
+		price, err := parsePrice(l[0])
+		if err != nil {
+			return nil, fmt.Errorf("level price %s: %w", l[0], err)
+		}
+		levels = append(levels, Level{Price: price, Quantity: parseOptional(l[1])})
+	}
+	return levels, nil
+}
+
+// parseOptional parses a numeric field, returning 0 when empty or invalid.
+func parseOptional(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	f, err := parsePrice(s)
+	if err != nil {
+		return 0
+	}
+	return f
+}
@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ====== CONFIG ======
+
+// Notifier delivers watchdog alerts; *telegram.Telegram satisfies it.
+type Notifier interface {
+	SendMessage(chatID, message string) error
+}
+
+type WatchdogConfig struct {
+	ConnTimeout    time.Duration            // max time without data frames; Pings don't count
+	SymbolTimeout  time.Duration            // max silence per symbol; negative disables symbol checks
+	SymbolTimeouts map[string]time.Duration // per-symbol overrides, e.g. for illiquid pairs
+	CheckInterval  time.Duration
+	Notifier       Notifier
+	ChatID         string
+}
+
+const (
+	defaultConnTimeout   = 30 * time.Second
+	defaultSymbolTimeout = 2 * time.Minute
+	defaultCheckInterval = 5 * time.Second
+)
+
+// ====== MAIN STRUCT ======
+
+// Watchdog detects frozen feeds: a socket that stays open while BingX
+// stops pushing data. A stale connection or symbol forces a reconnect and
+// sends an alert.
+type Watchdog struct {
+	cfg       WatchdogConfig
+	lastFrame time.Time
+	lastSeen  map[string]time.Time
+	alerted   map[string]bool // acted on since the last reconnect
+	down      map[string]bool // alerted and not yet recovered
+	mu        sync.Mutex
+}
+
+func newWatchdog(cfg WatchdogConfig) *Watchdog {
+	if cfg.ConnTimeout <= 0 {
+		cfg.ConnTimeout = defaultConnTimeout
+	}
+	if cfg.SymbolTimeout == 0 {
+		cfg.SymbolTimeout = defaultSymbolTimeout
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaultCheckInterval
+	}
+	return &Watchdog{
+		cfg:      cfg,
+		lastSeen: make(map[string]time.Time),
+		alerted:  make(map[string]bool),
+		down:     make(map[string]bool),
+	}
+}
+
+// SetWatchdog enables stale-feed detection. Call it before Connect.
+func (ws *BingXWebSocket) SetWatchdog(cfg WatchdogConfig) {
+	ws.watchdog = newWatchdog(cfg)
+}
+
+// ====== TRACKING ======
+
+// reset marks the connection and every subscribed symbol as fresh after a
+// (re)connect, so symbols that go stale again are acted on again.
+func (w *Watchdog) reset(tokens []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.lastFrame = now
+	w.lastSeen = make(map[string]time.Time, len(tokens))
+	for _, t := range tokens {
+		w.lastSeen[parseSymbol(t)] = now
+	}
+	w.alerted = make(map[string]bool)
+}
+
+func (w *Watchdog) frame() {
+	w.mu.Lock()
+	w.lastFrame = time.Now()
+	w.mu.Unlock()
+}
+
+func (w *Watchdog) seen(symbol string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastSeen[symbol] = time.Now()
+	delete(w.alerted, symbol)
+	if w.down[symbol] {
+		delete(w.down, symbol)
+		w.notify(fmt.Sprintf("✅ <b>Feed recovered</b>: %s", symbol))
+	}
+}
+
+// ====== CHECK LOOP ======
+
+func (w *Watchdog) run(ctx context.Context, ws *BingXWebSocket) {
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// A connection without subscriptions has no data to wait for.
+		if !ws.connected() || len(ws.Tokens()) == 0 {
+			continue
+		}
+		if reason := w.check(); reason != "" {
+			log.Printf("⚠️ Stale feed: %s, forcing reconnect", reason)
+			ws.forceReconnect()
+		}
+	}
+}
+
+// check returns a description of the stale feed, or "" when all is fresh.
+func (w *Watchdog) check() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if age := now.Sub(w.lastFrame); age > w.cfg.ConnTimeout {
+		reason := fmt.Sprintf("connection silent for %s", age.Round(time.Second))
+		w.notify(fmt.Sprintf("⚠️ <b>Stale feed</b>: %s, reconnecting", reason))
+		return reason
+	}
+
+	var stale []string
+	for symbol, last := range w.lastSeen {
+		limit, ok := w.cfg.SymbolTimeouts[symbol]
+		if !ok {
+			limit = w.cfg.SymbolTimeout
+		}
+		if limit <= 0 || now.Sub(last) <= limit || w.alerted[symbol] {
+			continue
+		}
+		w.alerted[symbol] = true
+		w.down[symbol] = true
+		stale = append(stale, symbol)
+		w.notify(fmt.Sprintf("⚠️ <b>Stale feed</b>: %s no data for %s, reconnecting",
+			symbol, now.Sub(last).Round(time.Second)))
+	}
+	if len(stale) > 0 {
+		return fmt.Sprintf("no data for %v", stale)
+	}
+	return ""
+}
+
+// notify sends an alert without blocking the caller.
+func (w *Watchdog) notify(msg string) {
+	if w.cfg.Notifier == nil {
+		return
+	}
+	go func() {
+		if err := w.cfg.Notifier.SendMessage(w.cfg.ChatID, msg); err != nil {
+			log.Printf("Watchdog alert failed: %v", err)
+		}
+	}()
+}
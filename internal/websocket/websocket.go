@@ -3,11 +3,11 @@ package websocket
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -19,6 +19,7 @@ import (
 
 type PriceUpdate struct {
 	Type   string  `json:"type"`
+	Stream string  `json:"stream"` // "markPrice" or "lastPrice"
 	Symbol string  `json:"symbol"`
 	Price  float64 `json:"price"`
 }
@@ -29,11 +30,11 @@ type Channel struct {
 	DataType string `json:"dataType"`
 }
 
+// MarketData is the raw envelope of a market message; Data is decoded
+// according to the stream kind encoded in DataType.
 type MarketData struct {
-	DataType string `json:"dataType"`
-	Data     struct {
-		Price string `json:"p"`
-	} `json:"data"`
+	DataType string          `json:"dataType"`
+	Data     json.RawMessage `json:"data"`
 }
 
 // ====== MAIN STRUCT ======
@@ -42,77 +43,252 @@ type BingXWebSocket struct {
 	path           string
 	conn           *websocket.Conn
 	tokens         []string
-	messageHandler func(PriceUpdate)
+	handlers       Handlers
+	emit           func(Event)
+	subscribeDelay time.Duration
+	onReconnect    func(err error)
+	watchdog       *Watchdog
+	recorder       *Recorder
 	mu             sync.RWMutex
-	quit           chan struct{}
+	writeMu        sync.Mutex
+	ctx            context.Context // current session, nil when stopped
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup // listener, watchdog and shutdown goroutines
 }
 
 // ====== CONSTRUCTOR ======
 
+// NewBingXWebSocket creates a client that only reports price streams
+// (e.g. "BTC-USDT@markPrice") to messageHandler.
 func NewBingXWebSocket(tokens []string, messageHandler func(PriceUpdate)) *BingXWebSocket {
+	return NewBingXWebSocketWithHandlers(tokens, Handlers{OnPrice: messageHandler})
+}
+
+// NewBingXWebSocketWithHandlers creates a client that routes every supported
+// stream (price, depth, trade, kline, ticker, bookTicker) to its handler.
+func NewBingXWebSocketWithHandlers(tokens []string, handlers Handlers) *BingXWebSocket {
 	return &BingXWebSocket{
 		path:           "wss://open-api-swap.bingx.com/swap-market",
 		tokens:         tokens,
-		messageHandler: messageHandler,
-		quit:           make(chan struct{}),
+		handlers:       handlers,
+		emit:           handlers.Handle,
+		subscribeDelay: 100 * time.Millisecond,
 	}
 }
 
 // ====== CONNECTION ======
 
+// Connect starts a session that lives until Close.
 func (ws *BingXWebSocket) Connect() error {
-	conn, _, err := websocket.DefaultDialer.Dial(ws.path, nil)
+	return ws.ConnectContext(context.Background())
+}
+
+// ConnectContext starts a session that ends when ctx is cancelled or Close
+// is called. A closed client can be connected again.
+func (ws *BingXWebSocket) ConnectContext(parent context.Context) error {
+	ws.mu.Lock()
+	if ws.ctx != nil {
+		ws.mu.Unlock()
+		return fmt.Errorf("WebSocket already running")
+	}
+	ctx, cancel := context.WithCancel(parent)
+	ws.ctx, ws.cancel = ctx, cancel
+	ws.mu.Unlock()
+
+	if err := ws.connect(ctx); err != nil {
+		ws.Close()
+		return err
+	}
+
+	// Unblock the listener as soon as the session ends, and free the
+	// client for another ConnectContext unless Close got there first.
+	ws.wg.Add(1)
+	go func() {
+		defer ws.wg.Done()
+		<-ctx.Done()
+
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		if ws.ctx != ctx {
+			return
+		}
+		ws.ctx, ws.cancel = nil, nil
+		cancel()
+		if ws.conn != nil {
+			_ = ws.conn.Close()
+			ws.conn = nil
+		}
+	}()
+
+	if ws.watchdog != nil {
+		ws.wg.Add(1)
+		go func() {
+			defer ws.wg.Done()
+			ws.watchdog.run(ctx, ws)
+		}()
+	}
+	return nil
+}
+
+// Run connects and blocks until ctx is cancelled, then shuts down and waits
+// for every goroutine to exit.
+func (ws *BingXWebSocket) Run(ctx context.Context) error {
+	if err := ws.ConnectContext(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	if err := ws.Close(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// connect dials, subscribes and then starts the listener; it is used for
+// the first connection and every reconnect of a session. On error the
+// caller drops the connection, and no listener is left running on it.
+func (ws *BingXWebSocket) connect(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, ws.path, nil)
 	if err != nil {
 		return fmt.Errorf("WebSocket connection failed: %w", err)
 	}
 
 	ws.mu.Lock()
+	if ctx.Err() != nil {
+		ws.mu.Unlock()
+		conn.Close()
+		return ctx.Err()
+	}
 	ws.conn = conn
 	ws.mu.Unlock()
 
 	log.Println("✅ WebSocket connected")
 
-	go ws.listen()
-	return ws.subscribeAll()
+	if ws.watchdog != nil {
+		ws.watchdog.reset(ws.Tokens())
+	}
+
+	if err := ws.subscribeAll(); err != nil {
+		return err
+	}
+	ws.wg.Add(1)
+	go ws.listen(ctx, conn)
+	return nil
+}
+
+// Tokens returns a copy of the subscribed data types.
+func (ws *BingXWebSocket) Tokens() []string {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return append([]string(nil), ws.tokens...)
+}
+
+// current reports whether conn is still the client's connection; a
+// listener on a dropped connection exits without reconnecting.
+func (ws *BingXWebSocket) current(conn *websocket.Conn) bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.conn == conn
+}
+
+// connected reports whether the client currently holds an open connection.
+func (ws *BingXWebSocket) connected() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.conn != nil
 }
 
 // subscribeAll subscribes to all tokens with a short delay to avoid flooding
 func (ws *BingXWebSocket) subscribeAll() error {
-	for i, token := range ws.tokens {
-		channel := Channel{
-			ID:       uuid.New().String(),
-			ReqType:  "sub",
-			DataType: token,
+	tokens := ws.Tokens()
+	for i, token := range tokens {
+		if err := ws.send("sub", token); err != nil {
+			return err
 		}
 
-		data, err := json.Marshal(channel)
-		if err != nil {
-			return fmt.Errorf("marshal channel %s: %w", token, err)
+		log.Printf("Subscribed to %s (%d/%d)", token, i+1, len(tokens))
+		if i < len(tokens)-1 {
+			time.Sleep(ws.subscribeDelay)
 		}
+	}
+	return nil
+}
 
-		ws.mu.RLock()
-		conn := ws.conn
-		ws.mu.RUnlock()
+// setTokens replaces the subscription set, sending unsub/sub requests for
+// the difference when connected.
+func (ws *BingXWebSocket) setTokens(tokens []string) error {
+	ws.mu.Lock()
+	old := ws.tokens
+	ws.tokens = append([]string(nil), tokens...)
+	live := ws.conn != nil
+	ws.mu.Unlock()
 
-		if conn == nil {
-			return fmt.Errorf("WebSocket not connected")
-		}
+	if !live {
+		return nil
+	}
 
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			return fmt.Errorf("subscribe %s: %w", token, err)
+	want := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		want[t] = true
+	}
+	have := make(map[string]bool, len(old))
+	for _, t := range old {
+		have[t] = true
+		if !want[t] {
+			if err := ws.send("unsub", t); err != nil {
+				return err
+			}
 		}
-
-		log.Printf("Subscribed to %s (%d/%d)", token, i+1, len(ws.tokens))
-		if i < len(ws.tokens)-1 {
-			time.Sleep(100 * time.Millisecond)
+	}
+	for _, t := range tokens {
+		if have[t] {
+			continue
+		}
+		if err := ws.send("sub", t); err != nil {
+			return err
 		}
+		time.Sleep(ws.subscribeDelay)
+	}
+	return nil
+}
+
+// send writes a sub/unsub request for a single data type.
+func (ws *BingXWebSocket) send(reqType, token string) error {
+	channel := Channel{
+		ID:       uuid.New().String(),
+		ReqType:  reqType,
+		DataType: token,
+	}
+
+	data, err := json.Marshal(channel)
+	if err != nil {
+		return fmt.Errorf("marshal channel %s: %w", token, err)
+	}
+
+	if err := ws.write(data); err != nil {
+		return fmt.Errorf("%s %s: %w", reqType, token, err)
 	}
 	return nil
 }
 
+// write serializes writes, gorilla connections allow only one concurrent writer.
+func (ws *BingXWebSocket) write(data []byte) error {
+	ws.mu.RLock()
+	conn := ws.conn
+	ws.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
 // ====== MESSAGE LOOP ======
 
-func (ws *BingXWebSocket) listen() {
+func (ws *BingXWebSocket) listen(ctx context.Context, conn *websocket.Conn) {
+	defer ws.wg.Done()
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("Recovered in listener: %v", r)
@@ -120,24 +296,13 @@ func (ws *BingXWebSocket) listen() {
 	}()
 
 	for {
-		ws.mu.RLock()
-		conn := ws.conn
-		ws.mu.RUnlock()
-
-		if conn == nil {
-			return
-		}
-
-		select {
-		case <-ws.quit:
-			return
-		default:
-		}
-
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
+			if ctx.Err() != nil || !ws.current(conn) {
+				return
+			}
 			log.Printf("WebSocket read error: %v", err)
-			ws.reconnect()
+			ws.reconnect(ctx)
 			return
 		}
 
@@ -148,17 +313,52 @@ func (ws *BingXWebSocket) listen() {
 // ====== MESSAGE HANDLING ======
 
 func (ws *BingXWebSocket) handleMessage(msg []byte) {
-	data, err := decompress(msg)
+	ws.mu.RLock()
+	rec := ws.recorder
+	ws.mu.RUnlock()
+
+	received := time.Now()
+	if rec != nil {
+		if err := rec.Record(FrameRaw, received, msg); err != nil {
+			log.Printf("Recording failed: %v", err)
+		}
+	}
+
+	buf, err := decompress(msg)
 	if err != nil {
 		log.Printf("Decompression failed: %v", err)
 		return
 	}
+	defer releaseBuffer(buf)
+	data := buf.Bytes()
+
+	if rec != nil {
+		if err := rec.Record(FrameDecoded, received, data); err != nil {
+			log.Printf("Recording failed: %v", err)
+		}
+	}
 
-	if bytes.Equal(data, []byte("Ping")) {
+	if bytes.Equal(data, pingFrame) {
 		ws.sendPong()
 		return
 	}
 
+	// Pings keep coming while a frozen feed sends no data, so only data
+	// frames count as a sign of life.
+	if ws.watchdog != nil {
+		ws.watchdog.frame()
+	}
+
+	// Price streams dominate traffic and skip encoding/json entirely.
+	if symbol, stream, price, ok := decodeFastPrice(data); ok {
+		ws.deliver(Event{
+			Kind:   StreamPrice,
+			Symbol: symbol,
+			Price:  PriceUpdate{Type: "priceUpdate", Stream: stream, Symbol: symbol, Price: price},
+		})
+		return
+	}
+
 	var market MarketData
 	if err := json.Unmarshal(data, &market); err != nil {
 		log.Printf("JSON decode failed: %v", err)
@@ -169,88 +369,227 @@ func (ws *BingXWebSocket) handleMessage(msg []byte) {
 }
 
 func (ws *BingXWebSocket) dispatch(m MarketData) {
-	if m.DataType == "" || m.Data.Price == "" {
+	if m.DataType == "" || len(m.Data) == 0 {
 		return
 	}
 
-	symbol := parseSymbol(m.DataType)
-	price, err := parsePrice(m.Data.Price)
+	events, err := decodeEvents(m)
 	if err != nil {
-		log.Printf("Invalid price %s: %v", m.Data.Price, err)
+		log.Printf("Invalid %s message: %v", m.DataType, err)
 		return
 	}
 
-	if ws.messageHandler != nil {
-		ws.messageHandler(PriceUpdate{
-			Type:   "priceUpdate",
-			Symbol: symbol,
-			Price:  price,
-		})
+	for _, ev := range events {
+		ws.deliver(ev)
+	}
+}
+
+func (ws *BingXWebSocket) deliver(ev Event) {
+	if ws.watchdog != nil {
+		ws.watchdog.seen(ev.Symbol)
 	}
+	ws.emit(ev)
 }
 
 // ====== UTILITIES ======
 
-func decompress(data []byte) ([]byte, error) {
-	reader, err := gzip.NewReader(bytes.NewReader(data))
-	if err != nil {
+var (
+	pingFrame = []byte("Ping")
+	pongFrame = []byte("Pong")
+
+	gzipPool   sync.Pool // *gzip.Reader, reused through Reset
+	readerPool = sync.Pool{New: func() any { return new(bytes.Reader) }}
+	bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+)
+
+// decompress inflates a gzip frame into a pooled buffer. The buffer must be
+// returned with releaseBuffer once its bytes are no longer referenced.
+func decompress(data []byte) (*bytes.Buffer, error) {
+	src := readerPool.Get().(*bytes.Reader)
+	src.Reset(data)
+	defer readerPool.Put(src)
+
+	var reader *gzip.Reader
+	if r, ok := gzipPool.Get().(*gzip.Reader); ok {
+		if err := r.Reset(src); err != nil {
+			gzipPool.Put(r)
+			return nil, err
+		}
+		reader = r
+	} else {
+		r, err := gzip.NewReader(src)
+		if err != nil {
+			return nil, err
+		}
+		reader = r
+	}
+	defer gzipPool.Put(reader)
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if _, err := buf.ReadFrom(reader); err != nil {
+		releaseBuffer(buf)
 		return nil, err
 	}
-	defer reader.Close()
-	return io.ReadAll(reader)
+	return buf, nil
+}
+
+func releaseBuffer(buf *bytes.Buffer) {
+	bufferPool.Put(buf)
 }
 
-func parseSymbol(s string) string {
-	if idx := strings.IndexRune(s, '@'); idx > 0 {
-		return s[:idx]
+// parseSymbol returns the part of a dataType before '@' without copying.
+func parseSymbol[T ~string | ~[]byte](s T) T {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '@' {
+			if i > 0 {
+				return s[:i]
+			}
+			break
+		}
 	}
 	return s
 }
 
-func parsePrice(s string) (float64, error) {
-	var f float64
-	_, err := fmt.Sscanf(s, "%f", &f)
-	return f, err
+// pow10 holds the powers of ten that are exact in float64.
+var pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
 }
 
-func (ws *BingXWebSocket) sendPong() {
-	ws.mu.RLock()
-	defer ws.mu.RUnlock()
-	if ws.conn != nil {
-		_ = ws.conn.WriteMessage(websocket.TextMessage, []byte("Pong"))
+// parsePrice parses plain decimals such as "43012.55" without allocating.
+// Mantissas up to 2^53 with at most 22 fractional digits convert exactly;
+// anything else (exponents, long inputs, malformed text) falls back to
+// strconv, which also produces the error.
+func parsePrice[T ~string | ~[]byte](s T) (float64, error) {
+	i, neg := 0, false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		i++
+	}
+
+	var mantissa uint64
+	digits, frac := 0, -1
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if digits == 19 {
+				return parseFloatSlow(s)
+			}
+			mantissa = mantissa*10 + uint64(c-'0')
+			digits++
+			if frac >= 0 {
+				frac++
+			}
+		case c == '.' && frac < 0:
+			frac = 0
+		default:
+			return parseFloatSlow(s)
+		}
+	}
+	if digits == 0 || mantissa > 1<<53 || frac >= len(pow10) {
+		return parseFloatSlow(s)
+	}
+
+	f := float64(mantissa)
+	if frac > 0 {
+		f /= pow10[frac]
 	}
+	if neg {
+		f = -f
+	}
+	return f, nil
+}
+
+func parseFloatSlow[T ~string | ~[]byte](s T) (float64, error) {
+	return strconv.ParseFloat(string(s), 64)
+}
+
+func (ws *BingXWebSocket) sendPong() {
+	_ = ws.write(pongFrame)
 }
 
 // ====== RECONNECT & CLOSE ======
 
-func (ws *BingXWebSocket) reconnect() {
-	log.Println("Attempting WebSocket reconnection...")
-	ws.Close()
-	time.Sleep(2 * time.Second)
-	if err := ws.Connect(); err != nil {
+// reconnect retries with exponential backoff until it succeeds or the
+// session ends. onReconnect is told about every attempt.
+func (ws *BingXWebSocket) reconnect(ctx context.Context) {
+	ws.dropConn()
+
+	backoff := 2 * time.Second
+	for {
+		log.Println("Attempting WebSocket reconnection...")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		err := ws.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if ws.onReconnect != nil {
+			ws.onReconnect(err)
+		}
+		if err == nil {
+			log.Println("Reconnected successfully")
+			return
+		}
+
 		log.Printf("Reconnection failed: %v", err)
-	} else {
-		log.Println("Reconnected successfully")
+		ws.dropConn()
+		if backoff < time.Minute {
+			backoff *= 2
+		}
 	}
 }
 
-func (ws *BingXWebSocket) Close() error {
+// forceReconnect closes the socket so the blocked read fails and the
+// listener reconnects.
+func (ws *BingXWebSocket) forceReconnect() {
+	ws.mu.RLock()
+	conn := ws.conn
+	ws.mu.RUnlock()
+
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// dropConn releases the current connection without stopping the client.
+func (ws *BingXWebSocket) dropConn() {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
-	select {
-	case <-ws.quit:
-	default:
-		close(ws.quit)
+	if ws.conn != nil {
+		_ = ws.conn.Close()
+		ws.conn = nil
+	}
+}
+
+// Close ends the session and waits for all its goroutines to exit. It must
+// not be called from a handler, which runs on the listener goroutine.
+func (ws *BingXWebSocket) Close() error {
+	ws.mu.Lock()
+	if ws.cancel != nil {
+		ws.cancel()
 	}
+	ws.ctx, ws.cancel = nil, nil
 
+	var err error
 	if ws.conn != nil {
-		err := ws.conn.Close()
+		err = ws.conn.Close()
 		ws.conn = nil
-		if err != nil {
-			return fmt.Errorf("error closing WebSocket: %w", err)
-		}
-		log.Println("WebSocket closed")
 	}
+	ws.mu.Unlock()
+
+	ws.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("error closing WebSocket: %w", err)
+	}
+	log.Println("WebSocket closed")
 	return nil
 }
@@ -0,0 +1,164 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func gzipFrame(tb testing.TB, s string) []byte {
+	tb.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		tb.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const markPriceMsg = `{"code":0,"dataType":"BTC-USDT@markPrice","data":{"e":"markPriceUpdate","E":1730366400000,"s":"BTC-USDT","p":"72015.35"}}`
+
+func TestParsePrice(t *testing.T) {
+	for _, s := range []string{"0", "1", "72015.35", "0.00001234", "-3.5", "+2.25", "123456789.123456", "1e-5", "12345678901234567890.5"} {
+		want, _ := strconv.ParseFloat(s, 64)
+		got, err := parsePrice(s)
+		if err != nil || got != want {
+			t.Errorf("parsePrice(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", ".", "-", "1.2.3", "abc"} {
+		if _, err := parsePrice(s); err == nil {
+			t.Errorf("parsePrice(%q) expected error", s)
+		}
+	}
+}
+
+func TestDecodeFastPrice(t *testing.T) {
+	symbol, stream, price, ok := decodeFastPrice([]byte(markPriceMsg))
+	if !ok || symbol != "BTC-USDT" || stream != "markPrice" || price != 72015.35 {
+		t.Fatalf("got %q %q %v %v", symbol, stream, price, ok)
+	}
+	if _, _, _, ok := decodeFastPrice([]byte(`{"dataType":"BTC-USDT@trade","data":[{"p":"1"}]}`)); ok {
+		t.Fatal("trade stream must use the JSON path")
+	}
+}
+
+// ====== BENCHMARKS ======
+//
+// The *Legacy benchmarks reproduce the previous implementation
+// (gzip.NewReader + io.ReadAll, fmt.Sscanf, encoding/json) for comparison:
+//
+//	go test -bench . -benchmem ./internal/websocket
+
+func legacyDecompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func legacyParsePrice(s string) (float64, error) {
+	var f float64
+	_, err := fmt.Sscanf(s, "%f", &f)
+	return f, err
+}
+
+func legacyParseSymbol(s string) string {
+	if idx := strings.IndexRune(s, '@'); idx > 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	frame := gzipFrame(b, markPriceMsg)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, err := decompress(frame)
+		if err != nil {
+			b.Fatal(err)
+		}
+		releaseBuffer(buf)
+	}
+}
+
+func BenchmarkDecompressLegacy(b *testing.B) {
+	frame := gzipFrame(b, markPriceMsg)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := legacyDecompress(frame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsePrice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := parsePrice("72015.35"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsePriceLegacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := legacyParsePrice("72015.35"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandleMessage(b *testing.B) {
+	frame := gzipFrame(b, markPriceMsg)
+	var sink float64
+	ws := NewBingXWebSocket(nil, func(u PriceUpdate) { sink += u.Price })
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ws.handleMessage(frame)
+	}
+	if sink == 0 {
+		b.Fatal("handler not called")
+	}
+}
+
+func BenchmarkHandleMessageLegacy(b *testing.B) {
+	frame := gzipFrame(b, markPriceMsg)
+	var sink float64
+	handler := func(u PriceUpdate) { sink += u.Price }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := legacyDecompress(frame)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var m struct {
+			DataType string `json:"dataType"`
+			Data     struct {
+				Price string `json:"p"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &m); err != nil {
+			b.Fatal(err)
+		}
+		price, err := legacyParsePrice(m.Data.Price)
+		if err != nil {
+			b.Fatal(err)
+		}
+		handler(PriceUpdate{Type: "priceUpdate", Symbol: legacyParseSymbol(m.DataType), Price: price})
+	}
+	if sink == 0 {
+		b.Fatal("handler not called")
+	}
+}